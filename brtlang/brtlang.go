@@ -0,0 +1,270 @@
+// Package brtlang lets Go programs embed the brtlang interpreter.
+//
+//	interp := brtlang.New(brtlang.WithStdout(&buf), brtlang.WithGlobal("name", "fam"))
+//	val, err := interp.Eval([]byte(`yap("sup " + name);`))
+package brtlang
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/0xmukesh/interpreter/internal/ast"
+	"github.com/0xmukesh/interpreter/internal/compiler"
+	"github.com/0xmukesh/interpreter/internal/diagnostics"
+	"github.com/0xmukesh/interpreter/internal/evaluator"
+	"github.com/0xmukesh/interpreter/internal/helpers"
+	"github.com/0xmukesh/interpreter/internal/lexer"
+	"github.com/0xmukesh/interpreter/internal/parser"
+	"github.com/0xmukesh/interpreter/internal/resolver"
+	"github.com/0xmukesh/interpreter/internal/runner"
+	"github.com/0xmukesh/interpreter/internal/runtime"
+	"github.com/0xmukesh/interpreter/internal/vm"
+)
+
+// Value is a brtlang value. Its `Value` field holds one of
+//
+//	nil, bool, string, int64, float64  nada, bet/cap, strings, ints and floats
+//	*big.Int                           big ints, such as 123n
+//	*Decimal                           decimals, such as 1.5d
+//	*List, *Map                        lists and maps, shared by reference with the program
+//
+// or a function, which hosts can only print or pass back to the program. `String` and
+// `TypeName` work for any value
+type Value = runtime.RuntimeValue
+
+// Decimal is an exact decimal number, its value is `Unscaled` / 10^`Scale`
+type Decimal = runtime.Decimal
+
+// List is a brtlang list, see Value
+type List = runtime.List
+
+// Map is a brtlang map. its `Get`, `Set` and `Delete` methods treat keys like brtlang code does,
+// `Keys` holds the keys in insertion order
+type Map = runtime.Map
+
+// NativeFn is a go function callable from brtlang code, see WithNativeFn. its result is converted
+// like the value of WithGlobal, so it can return any go integer or float type besides the types
+// listed on Value
+type NativeFn = runtime.NativeFn
+
+type Option func(*Interpreter)
+
+// WithStdout sets the writer `yap` prints to. defaults to os.Stdout
+func WithStdout(w io.Writer) Option {
+	return func(i *Interpreter) {
+		i.runtime.Stdout = w
+	}
+}
+
+// WithStderr sets the writer errors are reported to by Run. defaults to os.Stderr
+func WithStderr(w io.Writer) Option {
+	return func(i *Interpreter) {
+		i.runtime.Stderr = w
+	}
+}
+
+// WithGlobal declares a variable in the global environment before any code runs.
+// go integer types are converted to brtlang ints and go float types to floats
+func WithGlobal(name string, value interface{}) Option {
+	return func(i *Interpreter) {
+		i.runtime.CurrEnv().SetVar(name, runtime.FromGo(value))
+	}
+}
+
+// WithNativeFn registers `fn` so brtlang code can call it as `name(...)` with exactly `arity`
+// arguments, or with any number of arguments if `arity` is negative. an error returned by `fn`
+// is reported as a runtime error at the call site
+func WithNativeFn(name string, arity int, fn NativeFn) Option {
+	return func(i *Interpreter) {
+		i.runtime.RegisterNativeFn(name, arity, fn)
+	}
+}
+
+// WithTruthyLogic makes `&&`, `||` and `!` accept any value instead of only bools. `&&` and `||`
+// then return the operand which decided the result, so `name || "anon"` falls back to "anon" when
+// name is nada, cap, 0, "" or an empty list or map
+func WithTruthyLogic() Option {
+	return func(i *Interpreter) {
+		i.runtime.TruthyLogic = true
+	}
+}
+
+// WithVM compiles programs to bytecode and runs them on a virtual machine instead of walking
+// the syntax tree, which is a lot faster for long running programs
+func WithVM() Option {
+	return func(i *Interpreter) {
+		i.useVM = true
+	}
+}
+
+// WithIncremental lets functions use globals which a later call to Eval or Run declares, like
+// the lines of a REPL session do. calling a function which uses a global that was never declared
+// is then a runtime error rather than being rejected before the program runs
+func WithIncremental() Option {
+	return func(i *Interpreter) {
+		i.incremental = true
+	}
+}
+
+// WithFilename sets the name of the file the source comes from, errors are then reported at
+// "file:line:col" instead of "line:col"
+func WithFilename(name string) Option {
+	return func(i *Interpreter) {
+		i.filename = name
+	}
+}
+
+// WithColor colours the errors and warnings reported to the stderr writer, meant for terminals
+func WithColor() Option {
+	return func(i *Interpreter) {
+		i.color = true
+	}
+}
+
+// Interpreter runs brtlang programs against a single runtime, so globals declared
+// by one call to Eval are visible to the following ones
+type Interpreter struct {
+	runtime     *runtime.Runtime
+	useVM       bool
+	incremental bool
+	filename    string
+	color       bool
+}
+
+func New(opts ...Option) *Interpreter {
+	vars := make(runtime.RuntimeVarMapping)
+	globalEnv := runtime.NewEnvironment(vars, nil)
+
+	i := &Interpreter{
+		runtime: runtime.NewRuntime(&[]*runtime.Environment{globalEnv}),
+	}
+
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	return i
+}
+
+// Eval lexes, parses, resolves and runs `src`. it returns the value of the last top-level node,
+// which is nada unless that node is an expression. the returned error is a *lexer.LexerError,
+// parser.ParserErrors holding every syntax error of `src`, *resolver.ResolverError, *compiler.CompilerError or *runtime.RuntimeError.
+// warnings found while resolving, such as unused variables, are written to the configured stderr
+// writer
+func (i *Interpreter) Eval(src []byte) (Value, error) {
+	l := lexer.NewLexer(src)
+	tkns, lexErr := helpers.ProcessTokens(l)
+	if lexErr != nil {
+		lexErr.File = i.filename
+		return Value{}, lexErr
+	}
+
+	p := parser.NewParser(tkns)
+
+	programAst, parseErrs := p.BuildAst()
+	if parseErrs != nil {
+		for _, err := range parseErrs {
+			err.File = i.filename
+		}
+
+		return Value{}, parseErrs
+	}
+
+	res := resolver.NewResolver(i.isKnownGlobal)
+	res.Incremental = i.incremental
+
+	warnings, resolveErr := res.Resolve(programAst)
+	if resolveErr != nil {
+		resolveErr.File = i.filename
+		return Value{}, resolveErr
+	}
+
+	for _, warning := range warnings {
+		warning.File = i.filename
+		i.report(src, warning)
+	}
+
+	if i.useVM {
+		return i.evalOnVM(programAst)
+	}
+
+	e := evaluator.NewEvaluator(programAst, i.runtime)
+	r := runner.NewRunner(programAst, i.runtime, e)
+
+	var last Value
+
+	for !r.IsAtEnd() {
+		val, err := r.Run()
+		if err != nil {
+			// a runtime error can leave block environments behind on the stack
+			i.runtime.ResetToGlobalEnv()
+			err.File = i.filename
+			return Value{}, err
+		}
+
+		if val != nil {
+			last = *val
+		} else {
+			last = Value{}
+		}
+	}
+
+	return last, nil
+}
+
+// reports whether `name` is a global which exists before the program runs
+func (i *Interpreter) isKnownGlobal(name string) bool {
+	if _, ok := i.runtime.GlobalEnv().Vars[name]; ok {
+		return true
+	}
+
+	_, ok := i.runtime.GetNativeFn(name)
+	return ok
+}
+
+func (i *Interpreter) evalOnVM(programAst ast.Ast) (Value, error) {
+	function, compileErr := compiler.Compile(programAst)
+	if compileErr != nil {
+		compileErr.File = i.filename
+		return Value{}, compileErr
+	}
+
+	val, err := vm.NewVM(i.runtime).Run(function)
+	if err != nil {
+		err.File = i.filename
+		return Value{}, err
+	}
+
+	return *val, nil
+}
+
+// Run is like Eval but also reports the error to the configured stderr writer, along with the
+// source it was caused by
+func (i *Interpreter) Run(src []byte) error {
+	_, err := i.Eval(src)
+	if err != nil {
+		i.report(src, err)
+	}
+
+	return err
+}
+
+// writes `err` to the stderr writer, errors of the interpreter's stages are rendered with a snippet
+// of `src`. errors joining several errors are reported one by one
+func (i *Interpreter) report(src []byte, err error) {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			i.report(src, e)
+		}
+
+		return
+	}
+
+	d, ok := err.(diagnostics.Diagnosable)
+	if !ok {
+		fmt.Fprintln(i.runtime.Stderr, err.Error())
+		return
+	}
+
+	fmt.Fprint(i.runtime.Stderr, diagnostics.NewRenderer(src, i.filename, i.color).Render(d.Diagnostic()))
+}
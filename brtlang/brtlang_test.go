@@ -0,0 +1,167 @@
+package brtlang_test
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/0xmukesh/interpreter/brtlang"
+	"github.com/0xmukesh/interpreter/internal/lexer"
+	"github.com/0xmukesh/interpreter/internal/parser"
+	"github.com/0xmukesh/interpreter/internal/resolver"
+	"github.com/0xmukesh/interpreter/internal/runtime"
+)
+
+// backends runs each test against the tree walker and the vm
+var backends = map[string][]brtlang.Option{
+	"tree walker": nil,
+	"vm":          {brtlang.WithVM()},
+}
+
+func TestNativeFnResultsAreConverted(t *testing.T) {
+	natives := map[string]brtlang.NativeFn{
+		"int":     func([]brtlang.Value) (brtlang.Value, error) { return brtlang.Value{Value: 5}, nil },
+		"uint8":   func([]brtlang.Value) (brtlang.Value, error) { return brtlang.Value{Value: uint8(5)}, nil },
+		"float32": func([]brtlang.Value) (brtlang.Value, error) { return brtlang.Value{Value: float32(5)}, nil },
+	}
+
+	for backend, opts := range backends {
+		for name, fn := range natives {
+			t.Run(backend+"/"+name, func(t *testing.T) {
+				var out bytes.Buffer
+
+				opts := append([]brtlang.Option{brtlang.WithStdout(&out), brtlang.WithNativeFn("five", 0, fn)}, opts...)
+				if _, err := brtlang.New(opts...).Eval([]byte(`yap(five() + 1);`)); err != nil {
+					t.Fatalf("unexpected error: %s", err.Error())
+				}
+
+				expected := "6\n"
+				if name == "float32" {
+					expected = "6.0\n"
+				}
+
+				if out.String() != expected {
+					t.Errorf("expected %q, got %q", expected, out.String())
+				}
+			})
+		}
+	}
+}
+
+func TestWithStdout(t *testing.T) {
+	for backend, opts := range backends {
+		t.Run(backend, func(t *testing.T) {
+			var out bytes.Buffer
+
+			opts := append([]brtlang.Option{brtlang.WithStdout(&out)}, opts...)
+			if _, err := brtlang.New(opts...).Eval([]byte(`yap("sup"); yap(1 + 2);`)); err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			if out.String() != "sup\n3\n" {
+				t.Errorf("expected %q, got %q", "sup\n3\n", out.String())
+			}
+		})
+	}
+}
+
+func TestWithGlobalConversions(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected interface{}
+	}{
+		{"int", 5, int64(5)},
+		{"int32", int32(-5), int64(-5)},
+		{"uint16", uint16(5), int64(5)},
+		{"uint64", uint64(5), int64(5)},
+		{"uint64 above int64", uint64(math.MaxUint64), float64(math.MaxUint64)},
+		{"float32", float32(1.5), float64(1.5)},
+		{"string", "fam", "fam"},
+		{"bool", true, true},
+		{"nil", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			val, err := brtlang.New(brtlang.WithGlobal("g", tt.value)).Eval([]byte("g;"))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			if val.Value != tt.expected {
+				t.Errorf("expected %v (%T), got %v (%T)", tt.expected, tt.expected, val.Value, val.Value)
+			}
+		})
+	}
+
+	// converted ints work with the int operators
+	val, err := brtlang.New(brtlang.WithGlobal("n", 7)).Eval([]byte("n / 2 + n % 2;"))
+	if err != nil || val.Value != int64(4) {
+		t.Errorf("expected 4, got %v (error %v)", val.Value, err)
+	}
+}
+
+func TestEvalKeepsGlobals(t *testing.T) {
+	for backend, opts := range backends {
+		t.Run(backend, func(t *testing.T) {
+			interp := brtlang.New(opts...)
+
+			if _, err := interp.Eval([]byte("rizz xs = [1]; skibidi add(x) { push(xs, x); }")); err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			val, err := interp.Eval([]byte("add(2); xs;"))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			list, ok := val.Value.(*brtlang.List)
+			if !ok || list.String() != "[1, 2]" {
+				t.Errorf("expected [1, 2], got %s", val.String())
+			}
+		})
+	}
+}
+
+func TestEvalErrorTypes(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		check func(err error) bool
+	}{
+		{"lexer", `yap("open);`, func(err error) bool { _, ok := err.(*lexer.LexerError); return ok }},
+		{"parser", "yap(;", func(err error) bool { _, ok := err.(parser.ParserErrors); return ok }},
+		{"resolver", "yap(nope);", func(err error) bool { _, ok := err.(*resolver.ResolverError); return ok }},
+		{"runtime", "yap(1 / 0);", func(err error) bool { _, ok := err.(*runtime.RuntimeError); return ok }},
+	}
+
+	for backend, opts := range backends {
+		for _, tt := range tests {
+			t.Run(backend+"/"+tt.name, func(t *testing.T) {
+				_, err := brtlang.New(opts...).Eval([]byte(tt.src))
+				if err == nil || !tt.check(err) {
+					t.Errorf("expected a %s error, got %T: %v", tt.name, err, err)
+				}
+			})
+		}
+	}
+}
+
+func TestRunReportsErrors(t *testing.T) {
+	var out, errOut bytes.Buffer
+
+	interp := brtlang.New(brtlang.WithStdout(&out), brtlang.WithStderr(&errOut), brtlang.WithFilename("main.brt"))
+	if err := interp.Run([]byte("yap(1);\nyap(1 / 0);")); err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	if out.String() != "1\n" {
+		t.Errorf("expected the output before the error, got %q", out.String())
+	}
+
+	if !strings.Contains(errOut.String(), "error[E011]") || !strings.Contains(errOut.String(), "main.brt:2:5") {
+		t.Errorf("expected the error to be reported at main.brt:2:5, got %q", errOut.String())
+	}
+}
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"path"
 
+	"github.com/0xmukesh/interpreter/brtlang"
 	"github.com/0xmukesh/interpreter/internal/commands"
 	"github.com/0xmukesh/interpreter/internal/utils"
 )
@@ -12,12 +14,41 @@ import (
 func main() {
 	args := os.Args
 
-	if len(args) < 3 {
+	if len(args) < 2 {
 		utils.EPrint("invalid usage")
 	}
 
 	command := args[1]
-	filename := args[2]
+
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	truthy := flags.Bool("truthy", false, "let &&, || and ! work on any value")
+	useVM := flags.Bool("vm", false, "compile to bytecode and run it on the vm")
+	color := flags.Bool("color", false, "colour errors and warnings")
+	if err := flags.Parse(args[2:]); err != nil {
+		os.Exit(2)
+	}
+
+	var opts []brtlang.Option
+	if *truthy {
+		opts = append(opts, brtlang.WithTruthyLogic())
+	}
+	if *useVM {
+		opts = append(opts, brtlang.WithVM())
+	}
+	if *color {
+		opts = append(opts, brtlang.WithColor())
+	}
+
+	if command == "repl" {
+		commands.ReplCmdHandler(os.Stdin, os.Stdout, os.Stderr, opts...)
+		return
+	}
+
+	if flags.NArg() < 1 {
+		utils.EPrint("invalid usage")
+	}
+
+	filename := flags.Arg(0)
 
 	ext := path.Ext(filename)
 	if ext != ".brt" {
@@ -30,7 +61,11 @@ func main() {
 	}
 
 	if command == "run" {
-		commands.RunCmdHandler(src)
+		opts = append(opts, brtlang.WithFilename(filename))
+
+		if err := commands.RunCmdHandler(src, opts...); err != nil {
+			os.Exit(1)
+		}
 	} else {
 		utils.EPrint("invalid command\n")
 	}
@@ -1,5 +1,7 @@
 package ast
 
+import "github.com/0xmukesh/interpreter/internal/tokens"
+
 type AstNodeType int
 
 const (
@@ -8,6 +10,7 @@ const (
 )
 
 type AstNodeValue interface {
+	GetSpan() tokens.Span
 	IsExpr() bool
 	IsStmt() bool
 	isAstValue() bool
@@ -37,6 +40,16 @@ func (n AstNode) ExtractExpr() Expr {
 	return expr
 }
 
+// string representation of the node's expression, used when printing nodes which might be statements
+func (n AstNode) ParseNode() string {
+	expr := n.ExtractExpr()
+	if expr == nil {
+		return "<stmt>"
+	}
+
+	return expr.ParseExpr()
+}
+
 type Ast []AstNode
 
 func NewAstNode(nodeType AstNodeType, value AstNodeValue) *AstNode {
@@ -45,3 +58,21 @@ func NewAstNode(nodeType AstNodeType, value AstNodeValue) *AstNode {
 		Value: value,
 	}
 }
+
+// Binding tells where a variable lives. it's filled in by the resolver, until then (and for globals)
+// `Depth` is -1 and the variable is looked up by name in the global environment
+type Binding struct {
+	// number of environments between the one the variable is used in and the one it's declared in
+	Depth int
+	// index of the variable within the environment it's declared in
+	Slot int
+}
+
+func NewBinding() *Binding {
+	return &Binding{
+		Depth: -1,
+	}
+}
+func (b Binding) IsGlobal() bool {
+	return b.Depth < 0
+}
@@ -2,6 +2,7 @@ package ast
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/0xmukesh/interpreter/internal/tokens"
 )
@@ -14,35 +15,53 @@ const (
 	UNARY
 	BINARY
 	LOGICAL
+	FUNC
+	CALL
+	LIST
+	MAP
+	INDEX
+	INTERPOLATED_STR
+	INCREMENT
+	COMPOUND_ASSIGN
 )
 
 type Expr interface {
 	ParseExpr() string
 	GetLine() int
+	GetSpan() tokens.Span
 }
 
 type BaseExpr struct {
-	Line int
+	Span tokens.Span
 }
 
-func (e BaseExpr) GetLine() int     { return e.Line }
-func (e BaseExpr) IsExpr() bool     { return true }
-func (e BaseExpr) IsStmt() bool     { return false }
-func (e BaseExpr) isAstValue() bool { return true }
+func (e BaseExpr) GetLine() int         { return e.Span.Start.Line }
+func (e BaseExpr) GetSpan() tokens.Span { return e.Span }
+func (e BaseExpr) IsExpr() bool         { return true }
+func (e BaseExpr) IsStmt() bool         { return false }
+func (e BaseExpr) isAstValue() bool     { return true }
 
 type LiteralExpr struct {
 	BaseExpr
 	TokenType tokens.TokenType
 	Value     string
+	// only set for identifiers
+	Binding *Binding
 }
 
 func (e LiteralExpr) ParseExpr() string { return e.Value }
-func NewLiteralExpr(tokenType tokens.TokenType, value string, line int) LiteralExpr {
+func NewLiteralExpr(tokenType tokens.TokenType, value string, span tokens.Span) LiteralExpr {
+	var binding *Binding
+	if tokenType == tokens.IDENTIFIER {
+		binding = NewBinding()
+	}
+
 	return LiteralExpr{
 		TokenType: tokenType,
 		Value:     value,
+		Binding:   binding,
 		BaseExpr: BaseExpr{
-			Line: line,
+			Span: span,
 		},
 	}
 }
@@ -53,13 +72,13 @@ type GroupingExpr struct {
 }
 
 func (e GroupingExpr) ParseExpr() string {
-	return fmt.Sprintf("(group %s)", e.Node.ExtractExpr().ParseExpr())
+	return fmt.Sprintf("(group %s)", e.Node.ParseNode())
 }
-func NewGroupingExpr(node AstNode, line int) GroupingExpr {
+func NewGroupingExpr(node AstNode, span tokens.Span) GroupingExpr {
 	return GroupingExpr{
 		Node: node,
 		BaseExpr: BaseExpr{
-			Line: line,
+			Span: span,
 		},
 	}
 }
@@ -73,12 +92,12 @@ type UnaryExpr struct {
 func (e UnaryExpr) ParseExpr() string {
 	return fmt.Sprintf("(%s %s)", e.Operator.Literal(), e.Expr.ParseExpr())
 }
-func NewUnaryExpr(operator tokens.TokenType, expr Expr, line int) UnaryExpr {
+func NewUnaryExpr(operator tokens.TokenType, expr Expr, span tokens.Span) UnaryExpr {
 	return UnaryExpr{
 		Operator: operator,
 		Expr:     expr,
 		BaseExpr: BaseExpr{
-			Line: line,
+			Span: span,
 		},
 	}
 }
@@ -93,13 +112,13 @@ type BinaryExpr struct {
 func (e BinaryExpr) ParseExpr() string {
 	return fmt.Sprintf("(%s %s %s)", e.Operator.Literal(), e.Left.ParseExpr(), e.Right.ParseExpr())
 }
-func NewBinaryExpr(left Expr, operator tokens.TokenType, right Expr, line int) BinaryExpr {
+func NewBinaryExpr(left Expr, operator tokens.TokenType, right Expr, span tokens.Span) BinaryExpr {
 	return BinaryExpr{
 		Left:     left,
 		Operator: operator,
 		Right:    right,
 		BaseExpr: BaseExpr{
-			Line: line,
+			Span: span,
 		},
 	}
 }
@@ -114,13 +133,267 @@ type LogicalExpr struct {
 func (e LogicalExpr) ParseExpr() string {
 	return fmt.Sprintf("(%s %s %s)", e.Operator.Literal(), e.Left.ParseExpr(), e.Right.ParseExpr())
 }
-func NewLogicalExpr(left Expr, operator tokens.TokenType, right Expr, line int) LogicalExpr {
+func NewLogicalExpr(left Expr, operator tokens.TokenType, right Expr, span tokens.Span) LogicalExpr {
 	return LogicalExpr{
 		Left:     left,
 		Operator: operator,
 		Right:    right,
 		BaseExpr: BaseExpr{
-			Line: line,
+			Span: span,
+		},
+	}
+}
+
+// skibidi (...args) { ...node }
+type FuncExpr struct {
+	BaseExpr
+	Args []AstNode
+	Node AstNode
+}
+
+func (e FuncExpr) ParseExpr() string {
+	args := make([]string, len(e.Args))
+	for i, arg := range e.Args {
+		args[i] = arg.ExtractExpr().ParseExpr()
+	}
+
+	return fmt.Sprintf("(skibidi (%s))", strings.Join(args, ", "))
+}
+func NewFuncExpr(args []AstNode, node AstNode, span tokens.Span) FuncExpr {
+	return FuncExpr{
+		Args: args,
+		Node: node,
+		BaseExpr: BaseExpr{
+			Span: span,
+		},
+	}
+}
+
+// (callee)(...args)
+type CallExpr struct {
+	BaseExpr
+	Callee AstNode
+	Args   []AstNode
+}
+
+func (e CallExpr) ParseExpr() string {
+	parts := make([]string, 0, len(e.Args)+1)
+	parts = append(parts, e.Callee.ParseNode())
+	for _, arg := range e.Args {
+		parts = append(parts, arg.ParseNode())
+	}
+
+	return fmt.Sprintf("(call %s)", strings.Join(parts, " "))
+}
+func NewCallExpr(callee AstNode, args []AstNode, span tokens.Span) CallExpr {
+	return CallExpr{
+		Callee: callee,
+		Args:   args,
+		BaseExpr: BaseExpr{
+			Span: span,
+		},
+	}
+}
+
+// [...elements]
+type ListExpr struct {
+	BaseExpr
+	Elements []AstNode
+}
+
+func (e ListExpr) ParseExpr() string {
+	elements := make([]string, len(e.Elements))
+	for i, element := range e.Elements {
+		elements[i] = element.ParseNode()
+	}
+
+	return fmt.Sprintf("[%s]", strings.Join(elements, ", "))
+}
+func NewListExpr(elements []AstNode, span tokens.Span) ListExpr {
+	return ListExpr{
+		Elements: elements,
+		BaseExpr: BaseExpr{
+			Span: span,
+		},
+	}
+}
+
+type MapEntry struct {
+	Key   AstNode
+	Value AstNode
+}
+
+func NewMapEntry(key AstNode, value AstNode) MapEntry {
+	return MapEntry{
+		Key:   key,
+		Value: value,
+	}
+}
+
+// {...(key): (value)}
+type MapExpr struct {
+	BaseExpr
+	Entries []MapEntry
+}
+
+func (e MapExpr) ParseExpr() string {
+	entries := make([]string, len(e.Entries))
+	for i, entry := range e.Entries {
+		entries[i] = fmt.Sprintf("%s: %s", entry.Key.ParseNode(), entry.Value.ParseNode())
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(entries, ", "))
+}
+func NewMapExpr(entries []MapEntry, span tokens.Span) MapExpr {
+	return MapExpr{
+		Entries: entries,
+		BaseExpr: BaseExpr{
+			Span: span,
+		},
+	}
+}
+
+// (node)[index]
+type IndexExpr struct {
+	BaseExpr
+	Node  AstNode
+	Index AstNode
+}
+
+func (e IndexExpr) ParseExpr() string {
+	return fmt.Sprintf("(index %s %s)", e.Node.ParseNode(), e.Index.ParseNode())
+}
+func NewIndexExpr(node AstNode, index AstNode, span tokens.Span) IndexExpr {
+	return IndexExpr{
+		Node:  node,
+		Index: index,
+		BaseExpr: BaseExpr{
+			Span: span,
+		},
+	}
+}
+
+// "(text)${(expr)}(text)..."
+type InterpolatedStrExpr struct {
+	BaseExpr
+	// the text and the expressions of the string in order, the text is a string literal
+	Parts []AstNode
+}
+
+func (e InterpolatedStrExpr) ParseExpr() string {
+	parts := make([]string, len(e.Parts))
+	for i, part := range e.Parts {
+		if literal, ok := part.Value.(LiteralExpr); ok && literal.TokenType == tokens.STRING {
+			parts[i] = fmt.Sprintf("%q", literal.Value)
+		} else {
+			parts[i] = part.ParseNode()
+		}
+	}
+
+	return fmt.Sprintf("(interpolate %s)", strings.Join(parts, " "))
+}
+func NewInterpolatedStrExpr(parts []AstNode, span tokens.Span) InterpolatedStrExpr {
+	return InterpolatedStrExpr{
+		Parts: parts,
+		BaseExpr: BaseExpr{
+			Span: span,
+		},
+	}
+}
+
+// ++(name), --(name), (name)++ or (name)--. a prefix step evaluates to the variable's new value and
+// a postfix one to its value before the step
+type IncrementExpr struct {
+	BaseExpr
+	Name string
+	// either PLUS_PLUS or MINUS_MINUS
+	Operator tokens.TokenType
+	IsPrefix bool
+	Binding  *Binding
+}
+
+func (e IncrementExpr) ParseExpr() string {
+	if e.IsPrefix {
+		return fmt.Sprintf("(%s %s)", e.Operator.Literal(), e.Name)
+	}
+
+	return fmt.Sprintf("(%s %s)", e.Name, e.Operator.Literal())
+}
+func NewIncrementExpr(name string, operator tokens.TokenType, isPrefix bool, span tokens.Span) IncrementExpr {
+	return IncrementExpr{
+		Name:     name,
+		Operator: operator,
+		IsPrefix: isPrefix,
+		Binding:  NewBinding(),
+		BaseExpr: BaseExpr{
+			Span: span,
+		},
+	}
+}
+
+// (name) = (value). evaluates to the assigned value, so assignments can be chained
+type AssignExpr struct {
+	BaseExpr
+	Name    string
+	Value   Expr
+	Binding *Binding
+}
+
+func (e AssignExpr) ParseExpr() string {
+	return fmt.Sprintf("(= %s %s)", e.Name, e.Value.ParseExpr())
+}
+func NewAssignExpr(name string, value Expr, span tokens.Span) AssignExpr {
+	return AssignExpr{
+		Name:    name,
+		Value:   value,
+		Binding: NewBinding(),
+		BaseExpr: BaseExpr{
+			Span: span,
+		},
+	}
+}
+
+// (target)[index] = (value). evaluates to the assigned value
+type IndexAssignExpr struct {
+	BaseExpr
+	Target IndexExpr
+	Value  Expr
+}
+
+func (e IndexAssignExpr) ParseExpr() string {
+	return fmt.Sprintf("(= %s %s)", e.Target.ParseExpr(), e.Value.ParseExpr())
+}
+func NewIndexAssignExpr(target IndexExpr, value Expr, span tokens.Span) IndexAssignExpr {
+	return IndexAssignExpr{
+		Target: target,
+		Value:  value,
+		BaseExpr: BaseExpr{
+			Span: span,
+		},
+	}
+}
+
+// (name) += (value), or any other of -=, *=, /= and %=. evaluates to the variable's new value
+type CompoundAssignExpr struct {
+	BaseExpr
+	Name string
+	// the binary operator applied to the variable and the value, such as PLUS for +=
+	Operator tokens.TokenType
+	Value    Expr
+	Binding  *Binding
+}
+
+func (e CompoundAssignExpr) ParseExpr() string {
+	return fmt.Sprintf("(%s= %s %s)", e.Operator.Literal(), e.Name, e.Value.ParseExpr())
+}
+func NewCompoundAssignExpr(name string, operator tokens.TokenType, value Expr, span tokens.Span) CompoundAssignExpr {
+	return CompoundAssignExpr{
+		Name:     name,
+		Operator: operator,
+		Value:    value,
+		Binding:  NewBinding(),
+		BaseExpr: BaseExpr{
+			Span: span,
 		},
 	}
 }
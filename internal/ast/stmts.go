@@ -1,5 +1,7 @@
 package ast
 
+import "github.com/0xmukesh/interpreter/internal/tokens"
+
 type Scope int
 
 const (
@@ -11,50 +13,36 @@ type Stmt interface {
 	ParseStmt()
 	GetExpr() Expr
 	GetLine() int
+	GetSpan() tokens.Span
 }
 
 type BaseStmt struct {
-	Line int
+	Span tokens.Span
 }
 
-func (s BaseStmt) ParseStmt()       {}
-func (s BaseStmt) GetLine() int     { return s.Line }
-func (s BaseStmt) IsExpr() bool     { return false }
-func (s BaseStmt) IsStmt() bool     { return true }
-func (s BaseStmt) isAstValue() bool { return true }
+func (s BaseStmt) ParseStmt()           {}
+func (s BaseStmt) GetLine() int         { return s.Span.Start.Line }
+func (s BaseStmt) GetSpan() tokens.Span { return s.Span }
+func (s BaseStmt) IsExpr() bool         { return false }
+func (s BaseStmt) IsStmt() bool         { return true }
+func (s BaseStmt) isAstValue() bool     { return true }
 
 // rizz (name) = (node);
 type VarAssignStmt struct {
 	BaseStmt
-	Node AstNode
-	Name string
+	Node    AstNode
+	Name    string
+	Binding *Binding
 }
 
 func (s VarAssignStmt) GetExpr() Expr { return s.Node.ExtractExpr() }
-func NewVarAssignStmt(name string, node AstNode, line int) VarAssignStmt {
+func NewVarAssignStmt(name string, node AstNode, span tokens.Span) VarAssignStmt {
 	return VarAssignStmt{
-		Name: name,
-		Node: node,
-		BaseStmt: BaseStmt{
-			Line: line,
-		},
-	}
-}
-
-// (name) = (node);
-type VarReassignStmt struct {
-	BaseStmt
-	Node AstNode
-	Name string
-}
-
-func (s VarReassignStmt) GetExpr() Expr { return s.Node.ExtractExpr() }
-func NewVarReassignStmt(name string, node AstNode, line int) VarReassignStmt {
-	return VarReassignStmt{
-		Name: name,
-		Node: node,
+		Name:    name,
+		Node:    node,
+		Binding: NewBinding(),
 		BaseStmt: BaseStmt{
-			Line: line,
+			Span: span,
 		},
 	}
 }
@@ -66,11 +54,11 @@ type PrintStmt struct {
 }
 
 func (s PrintStmt) GetExpr() Expr { return s.Node.ExtractExpr() }
-func NewPrintStmt(node AstNode, line int) PrintStmt {
+func NewPrintStmt(node AstNode, span tokens.Span) PrintStmt {
 	return PrintStmt{
 		Node: node,
 		BaseStmt: BaseStmt{
-			Line: line,
+			Span: span,
 		},
 	}
 }
@@ -84,11 +72,11 @@ type CreateBlockStmt struct {
 }
 
 func (s CreateBlockStmt) GetExpr() Expr { return nil }
-func NewCreateBlockStmt(nodes []AstNode, line int) CreateBlockStmt {
+func NewCreateBlockStmt(nodes []AstNode, span tokens.Span) CreateBlockStmt {
 	return CreateBlockStmt{
 		Nodes: nodes,
 		BaseStmt: BaseStmt{
-			Line: line,
+			Span: span,
 		},
 	}
 }
@@ -98,10 +86,10 @@ type CloseBlockStmt struct {
 }
 
 func (s CloseBlockStmt) GetExpr() Expr { return nil }
-func NewCloseBlockStmt(line int) CloseBlockStmt {
+func NewCloseBlockStmt(span tokens.Span) CloseBlockStmt {
 	return CloseBlockStmt{
 		BaseStmt: BaseStmt{
-			Line: line,
+			Span: span,
 		},
 	}
 }
@@ -118,14 +106,14 @@ type IfStmt struct {
 }
 
 func (s IfStmt) GetExpr() Expr { return s.Node.ExtractExpr() }
-func NewIfStmt(node AstNode, ifBranch AstNode, elseIfBranches *[]ElseIfStmt, elseBranch *ElseStmt, line int) IfStmt {
+func NewIfStmt(node AstNode, ifBranch AstNode, elseIfBranches *[]ElseIfStmt, elseBranch *ElseStmt, span tokens.Span) IfStmt {
 	return IfStmt{
 		Node:           node,
 		IfBranch:       ifBranch,
 		ElseIfBranches: elseIfBranches,
 		ElseBranch:     elseBranch,
 		BaseStmt: BaseStmt{
-			Line: line,
+			Span: span,
 		},
 	}
 }
@@ -140,12 +128,12 @@ type ElseIfStmt struct {
 }
 
 func (s ElseIfStmt) GetExpr() Expr { return s.Node.ExtractExpr() }
-func NewElseIfStmt(node AstNode, branch AstNode, line int) ElseIfStmt {
+func NewElseIfStmt(node AstNode, branch AstNode, span tokens.Span) ElseIfStmt {
 	return ElseIfStmt{
 		Node:   node,
 		Branch: branch,
 		BaseStmt: BaseStmt{
-			Line: line,
+			Span: span,
 		},
 	}
 }
@@ -159,11 +147,11 @@ type ElseStmt struct {
 }
 
 func (s ElseStmt) GetExpr() Expr { return nil }
-func NewElseStmt(branch AstNode, line int) ElseStmt {
+func NewElseStmt(branch AstNode, span tokens.Span) ElseStmt {
 	return ElseStmt{
 		Branch: branch,
 		BaseStmt: BaseStmt{
-			Line: line,
+			Span: span,
 		},
 	}
 }
@@ -178,12 +166,12 @@ type WhileStmt struct {
 }
 
 func (s WhileStmt) GetExpr() Expr { return s.Node.ExtractExpr() }
-func NewWhileStmt(node AstNode, branch AstNode, line int) WhileStmt {
+func NewWhileStmt(node AstNode, branch AstNode, span tokens.Span) WhileStmt {
 	return WhileStmt{
 		Node:   node,
 		Branch: branch,
 		BaseStmt: BaseStmt{
-			Line: line,
+			Span: span,
 		},
 	}
 }
@@ -193,39 +181,21 @@ func NewWhileStmt(node AstNode, branch AstNode, line int) WhileStmt {
 //	}
 type FuncDeclarationStmt struct {
 	BaseStmt
-	Name string
-	Node AstNode
-	Args []AstNode
+	Name    string
+	Node    AstNode
+	Args    []AstNode
+	Binding *Binding
 }
 
 func (s FuncDeclarationStmt) GetExpr() Expr { return nil }
-func NewFuncDeclarationStmt(name string, args []AstNode, node AstNode, line int) FuncDeclarationStmt {
+func NewFuncDeclarationStmt(name string, args []AstNode, node AstNode, span tokens.Span) FuncDeclarationStmt {
 	return FuncDeclarationStmt{
-		Name: name,
-		Node: node,
-		Args: args,
+		Name:    name,
+		Node:    node,
+		Args:    args,
+		Binding: NewBinding(),
 		BaseStmt: BaseStmt{
-			Line: line,
-		},
-	}
-}
-
-// (name)(...args);
-type FuncCallStmt struct {
-	BaseStmt
-	Name       string
-	Args       []AstNode
-	ReturnNode AstNode
-}
-
-func (s FuncCallStmt) GetExpr() Expr { return s.ReturnNode.ExtractExpr() }
-func NewFuncCallStmt(name string, args []AstNode, returnNode AstNode, line int) FuncCallStmt {
-	return FuncCallStmt{
-		Name:       name,
-		Args:       args,
-		ReturnNode: returnNode,
-		BaseStmt: BaseStmt{
-			Line: line,
+			Span: span,
 		},
 	}
 }
@@ -237,43 +207,39 @@ type ReturnStmt struct {
 }
 
 func (s ReturnStmt) GetExpr() Expr { return s.Node.ExtractExpr() }
-func NewReturnStmt(node AstNode, line int) ReturnStmt {
+func NewReturnStmt(node AstNode, span tokens.Span) ReturnStmt {
 	return ReturnStmt{
 		Node: node,
 		BaseStmt: BaseStmt{
-			Line: line,
+			Span: span,
 		},
 	}
 }
 
-// (name)++;
-type IncrementStmt struct {
+// yeet;
+type BreakStmt struct {
 	BaseStmt
-	Name string
 }
 
-func (s IncrementStmt) GetExpr() Expr { return nil }
-func NewIncrementStmt(name string, line int) IncrementStmt {
-	return IncrementStmt{
-		Name: name,
+func (s BreakStmt) GetExpr() Expr { return nil }
+func NewBreakStmt(span tokens.Span) BreakStmt {
+	return BreakStmt{
 		BaseStmt: BaseStmt{
-			Line: line,
+			Span: span,
 		},
 	}
 }
 
-// (name)--;
-type DecrementStmt struct {
+// skrrt;
+type ContinueStmt struct {
 	BaseStmt
-	Name string
 }
 
-func (s DecrementStmt) GetExpr() Expr { return nil }
-func NewDecrementStmt(name string, line int) DecrementStmt {
-	return DecrementStmt{
-		Name: name,
+func (s ContinueStmt) GetExpr() Expr { return nil }
+func NewContinueStmt(span tokens.Span) ContinueStmt {
+	return ContinueStmt{
 		BaseStmt: BaseStmt{
-			Line: line,
+			Span: span,
 		},
 	}
 }
@@ -290,28 +256,14 @@ type ForStmt struct {
 }
 
 func (s ForStmt) GetExpr() Expr { return nil }
-func NewForStmt(node, init, condition, update AstNode, line int) ForStmt {
+func NewForStmt(node, init, condition, update AstNode, span tokens.Span) ForStmt {
 	return ForStmt{
 		Node:      node,
 		Init:      init,
 		Condition: condition,
 		Update:    update,
 		BaseStmt: BaseStmt{
-			Line: line,
-		},
-	}
-}
-
-// vibeCheck()
-type NativeClockFnStmt struct {
-	BaseStmt
-}
-
-func (s NativeClockFnStmt) GetExpr() Expr { return nil }
-func NewNativeClockFnStmt(line int) NativeClockFnStmt {
-	return NativeClockFnStmt{
-		BaseStmt: BaseStmt{
-			Line: line,
+			Span: span,
 		},
 	}
 }
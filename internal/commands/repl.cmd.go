@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/0xmukesh/interpreter/brtlang"
+	"github.com/0xmukesh/interpreter/internal/lexer"
+	"github.com/0xmukesh/interpreter/internal/tokens"
+)
+
+const (
+	REPL_PROMPT              = ">> "
+	REPL_CONTINUATION_PROMPT = ".. "
+)
+
+// ReplCmdHandler reads brtlang source line by line from `in` and runs it against a single interpreter,
+// so variables and functions declared on one line are visible on the following ones, and functions can
+// call the ones declared later on
+func ReplCmdHandler(in io.Reader, out io.Writer, errOut io.Writer, opts ...brtlang.Option) {
+	interpreter := brtlang.New(append([]brtlang.Option{brtlang.WithStdout(out), brtlang.WithStderr(errOut), brtlang.WithIncremental()}, opts...)...)
+
+	scanner := bufio.NewScanner(in)
+	var buf strings.Builder
+
+	fmt.Fprint(out, REPL_PROMPT)
+
+	for scanner.Scan() {
+		buf.WriteString(scanner.Text())
+		buf.WriteByte('\n')
+
+		src := []byte(buf.String())
+
+		tkns, err := lexer.NewLexer(src).LexAll()
+		// a `...` string can span lines, so keep reading until it's closed
+		if err != nil && err.Code == lexer.UNTERMINATED_RAW_STRING.Code {
+			fmt.Fprint(out, REPL_CONTINUATION_PROMPT)
+			continue
+		}
+
+		if err != nil {
+			// running the source reports the error the same way as every other error
+			_ = interpreter.Run(src)
+			buf.Reset()
+			fmt.Fprint(out, REPL_PROMPT)
+			continue
+		}
+
+		// keep reading lines until every opened block is closed
+		if braceDepth(tkns) > 0 {
+			fmt.Fprint(out, REPL_CONTINUATION_PROMPT)
+			continue
+		}
+
+		buf.Reset()
+		// errors are already reported by the interpreter, the session just moves on
+		_ = interpreter.Run(src)
+		fmt.Fprint(out, REPL_PROMPT)
+	}
+
+	fmt.Fprintln(out)
+}
+
+func braceDepth(tkns []tokens.Token) int {
+	depth := 0
+
+	for _, tkn := range tkns {
+		switch tkn.Type {
+		case tokens.LEFT_BRACE:
+			depth++
+		case tokens.RIGHT_BRACE:
+			depth--
+		}
+	}
+
+	return depth
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func runRepl(lines ...string) (string, string) {
+	var out, errOut bytes.Buffer
+
+	ReplCmdHandler(strings.NewReader(strings.Join(lines, "\n")+"\n"), &out, &errOut)
+	return out.String(), errOut.String()
+}
+
+func TestReplContinuesBlocks(t *testing.T) {
+	out, errOut := runRepl(
+		"rizz x = 1;",
+		"edging (x == 1) {",
+		`  yap("one");`,
+		"}",
+		"rizz s = `a",
+		"b`;",
+		"yap(s);",
+	)
+
+	expected := ">> >> .. .. one\n>> .. >> a\nb\n>> \n"
+	if out != expected {
+		t.Errorf("expected %q, got %q", expected, out)
+	}
+
+	if errOut != "" {
+		t.Errorf("unexpected errors: %s", errOut)
+	}
+}
+
+func TestReplRecoversFromErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		error string
+	}{
+		{"parser error", "yap(;", "error[P001]"},
+		{"runtime error", "yap(1 / 0);", "error[E011]"},
+		{"lexer error", `yap("open);`, "error[L"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, errOut := runRepl("rizz x = 1;", tt.line, "yap(x + 1);")
+
+			if !strings.Contains(errOut, tt.error) {
+				t.Errorf("expected %s to be reported, got %q", tt.error, errOut)
+			}
+
+			// the session keeps going with the variables declared before the error
+			if !strings.HasSuffix(out, ">> 2\n>> \n") {
+				t.Errorf("expected the line after the error to run, got %q", out)
+			}
+		})
+	}
+}
+
+func TestReplCallsFunctionsDeclaredLater(t *testing.T) {
+	out, errOut := runRepl(
+		"skibidi g() { bussin h(); }",
+		"yap(g());",
+		"skibidi h() { bussin 42; }",
+		"yap(g());",
+	)
+
+	if !strings.Contains(errOut, "error[E001]") {
+		t.Errorf("expected calling g before h exists to be a runtime error, got %q", errOut)
+	}
+
+	if !strings.HasSuffix(out, ">> 42\n>> \n") {
+		t.Errorf("expected g to call h once it's declared, got %q", out)
+	}
+}
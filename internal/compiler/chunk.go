@@ -0,0 +1,73 @@
+package compiler
+
+import (
+	"fmt"
+
+	"github.com/0xmukesh/interpreter/internal/runtime"
+	"github.com/0xmukesh/interpreter/internal/tokens"
+)
+
+// Chunk is a sequence of bytecode along with the constants it refers to. `Spans` holds the source
+// span of every byte in `Code`
+type Chunk struct {
+	Code      []byte
+	Spans     []tokens.Span
+	Constants []runtime.RuntimeValue
+
+	// indices of the string and number constants, used to de-duplicate them
+	constantIdxs map[interface{}]int
+}
+
+func NewChunk() *Chunk {
+	return &Chunk{
+		constantIdxs: make(map[interface{}]int),
+	}
+}
+
+func (c *Chunk) Write(b byte, span tokens.Span) {
+	c.Code = append(c.Code, b)
+	c.Spans = append(c.Spans, span)
+}
+
+// adds `val` to the constant pool and returns its index. strings and numbers are de-duplicated
+func (c *Chunk) AddConstant(val runtime.RuntimeValue) int {
+	switch val.Value.(type) {
+	case string, int64, float64:
+		if idx, ok := c.constantIdxs[val.Value]; ok {
+			return idx
+		}
+
+		c.constantIdxs[val.Value] = len(c.Constants)
+	}
+
+	c.Constants = append(c.Constants, val)
+	return len(c.Constants) - 1
+}
+
+// reads the big endian u16 operand at `offset`
+func (c *Chunk) ReadU16(offset int) int {
+	return int(c.Code[offset])<<8 | int(c.Code[offset+1])
+}
+
+// Function is a compiled function body. the top-level program is compiled into a function too
+type Function struct {
+	Name         string
+	Arity        int
+	UpvalueCount int
+	Chunk        *Chunk
+}
+
+func NewFunction(name string, arity int) *Function {
+	return &Function{
+		Name:  name,
+		Arity: arity,
+		Chunk: NewChunk(),
+	}
+}
+func (f Function) String() string {
+	if f.Name == "" {
+		return "<skibidi>"
+	}
+
+	return fmt.Sprintf("<skibidi %s>", f.Name)
+}
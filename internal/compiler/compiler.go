@@ -0,0 +1,882 @@
+package compiler
+
+import (
+	"math"
+
+	"github.com/0xmukesh/interpreter/internal/ast"
+	"github.com/0xmukesh/interpreter/internal/runtime"
+	"github.com/0xmukesh/interpreter/internal/tokens"
+)
+
+const MAX_LOCALS = 256
+
+type local struct {
+	name  string
+	depth int
+	// set if a closure refers to the local, so it has to be moved off the stack once it goes out of scope
+	isCaptured bool
+	// set for a function declared further down in its block. its slot is reserved upfront so closures
+	// can capture it, but code running before the declaration can't see it
+	isPending bool
+}
+
+// Undeclared fills the slot of a function declared further down in its block until the
+// declaration is reached, so closures calling the function too early report it as undefined
+type Undeclared struct {
+	Name string
+}
+
+type upvalue struct {
+	index   uint8
+	isLocal bool
+}
+
+type loop struct {
+	// scope depth the loop's body is nested in, locals declared deeper are dropped by `yeet` and `skrrt`
+	scopeDepth    int
+	breakJumps    []int
+	continueJumps []int
+}
+
+// Compiler compiles the body of a single function. variables declared outside of any block are
+// globals and looked up by name at runtime, every other variable is resolved to a stack slot (or an
+// upvalue if it belongs to an enclosing function) at compile time
+type Compiler struct {
+	enclosing  *Compiler
+	function   *Function
+	locals     []local
+	upvalues   []upvalue
+	scopeDepth int
+	loops      []*loop
+	span       tokens.Span
+	// set if the constant pool overflowed, which is only reported once the function is compiled
+	err *CompilerError
+}
+
+func newCompiler(enclosing *Compiler, function *Function) *Compiler {
+	return &Compiler{
+		enclosing: enclosing,
+		function:  function,
+		// slot 0 holds the function being called
+		locals: []local{{name: "", depth: 0}},
+	}
+}
+
+// Compile compiles a program into a function which takes no arguments and returns the value of the
+// program's last node, if it is an expression
+func Compile(program ast.Ast) (*Function, *CompilerError) {
+	c := newCompiler(nil, NewFunction("", 0))
+
+	for i, node := range program {
+		if i == len(program)-1 && producesValue(node) {
+			if err := c.compileValue(node); err != nil {
+				return nil, err
+			}
+
+			c.emit(OP_RETURN)
+			return c.function, c.err
+		}
+
+		if err := c.compileNode(node); err != nil {
+			return nil, err
+		}
+	}
+
+	c.emit(OP_NIL)
+	c.emit(OP_RETURN)
+
+	return c.function, c.err
+}
+
+// reports whether the node leaves a value behind when it is run by the tree-walking runner
+func producesValue(node ast.AstNode) bool {
+	switch node.Value.(type) {
+	case ast.Expr, ast.ReturnStmt:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *Compiler) chunk() *Chunk {
+	return c.function.Chunk
+}
+
+func (c *Compiler) emit(op OpCode) {
+	c.chunk().Write(byte(op), c.span)
+}
+
+func (c *Compiler) emitU8(op OpCode, operand uint8) {
+	c.emit(op)
+	c.chunk().Write(operand, c.span)
+}
+
+func (c *Compiler) emitU16(op OpCode, operand uint16) {
+	c.emit(op)
+	c.chunk().Write(byte(operand>>8), c.span)
+	c.chunk().Write(byte(operand), c.span)
+}
+
+// emits a jump with a placeholder offset and returns the offset of its operand, to be patched later
+// via `patchJump`
+func (c *Compiler) emitJump(op OpCode) int {
+	c.emitU16(op, math.MaxUint16)
+	return len(c.chunk().Code) - 2
+}
+
+// emits a jump taken if the condition on top of the stack is false. the condition is reported as
+// not being an `expected` expression if it isn't a bool
+func (c *Compiler) emitConditionJump(expected string) int {
+	offset := c.emitJump(OP_JUMP_IF_FALSE)
+
+	msgIdx := c.makeConstant(*runtime.NewRuntimeValue(expected))
+	c.chunk().Write(byte(msgIdx>>8), c.span)
+	c.chunk().Write(byte(msgIdx), c.span)
+
+	return offset
+}
+
+func (c *Compiler) patchJump(offset int) *CompilerError {
+	jump := len(c.chunk().Code) - offset - 2
+	if OpCode(c.chunk().Code[offset-1]) == OP_JUMP_IF_FALSE {
+		// skips the error message operand too
+		jump -= 2
+	}
+	if jump > math.MaxUint16 {
+		return NewCompilerError(JUMP_TOO_LONG, "", c.span)
+	}
+
+	c.chunk().Code[offset] = byte(jump >> 8)
+	c.chunk().Code[offset+1] = byte(jump)
+
+	return nil
+}
+
+func (c *Compiler) emitLoop(loopStart int) *CompilerError {
+	jump := len(c.chunk().Code) - loopStart + 3
+	if jump > math.MaxUint16 {
+		return NewCompilerError(JUMP_TOO_LONG, "", c.span)
+	}
+
+	c.emitU16(OP_LOOP, uint16(jump))
+	return nil
+}
+
+func (c *Compiler) makeConstant(val runtime.RuntimeValue) uint16 {
+	idx := c.chunk().AddConstant(val)
+	if idx > math.MaxUint16 {
+		if c.err == nil {
+			c.err = NewCompilerError(TOO_MANY_CONSTANTS, val.String(), c.span)
+		}
+
+		return 0
+	}
+
+	return uint16(idx)
+}
+
+func (c *Compiler) emitConstant(val runtime.RuntimeValue) {
+	c.emitU16(OP_CONSTANT, c.makeConstant(val))
+}
+
+func (c *Compiler) emitClosure(function *Function, upvalues []upvalue) {
+	c.emitU16(OP_CLOSURE, c.makeConstant(*runtime.NewRuntimeValue(function)))
+
+	for _, upvalue := range upvalues {
+		isLocal := uint8(0)
+		if upvalue.isLocal {
+			isLocal = 1
+		}
+
+		c.chunk().Write(isLocal, c.span)
+		c.chunk().Write(upvalue.index, c.span)
+	}
+}
+
+func (c *Compiler) beginScope() {
+	c.scopeDepth++
+}
+
+func (c *Compiler) endScope() {
+	c.scopeDepth--
+
+	for len(c.locals) > 0 && c.locals[len(c.locals)-1].depth > c.scopeDepth {
+		if c.locals[len(c.locals)-1].isCaptured {
+			c.emit(OP_CLOSE_UPVALUE)
+		} else {
+			c.emit(OP_POP)
+		}
+
+		c.locals = c.locals[:len(c.locals)-1]
+	}
+}
+
+// emits the code dropping every local declared deeper than `depth`, without ending their scopes. used
+// when jumping out of scopes
+func (c *Compiler) discardLocals(depth int) {
+	for i := len(c.locals) - 1; i >= 0 && c.locals[i].depth > depth; i-- {
+		if c.locals[i].isCaptured {
+			c.emit(OP_CLOSE_UPVALUE)
+		} else {
+			c.emit(OP_POP)
+		}
+	}
+}
+
+// declares a local in the current scope, its value is the one on top of the stack
+func (c *Compiler) addLocal(name string) *CompilerError {
+	for i := len(c.locals) - 1; i >= 0 && c.locals[i].depth == c.scopeDepth; i-- {
+		if name != "" && c.locals[i].name == name {
+			return NewCompilerError(IDENTIFIER_ALREADY_EXISTS, name, c.span)
+		}
+	}
+
+	if len(c.locals) >= MAX_LOCALS {
+		return NewCompilerError(TOO_MANY_LOCALS, name, c.span)
+	}
+
+	c.locals = append(c.locals, local{name: name, depth: c.scopeDepth})
+	return nil
+}
+
+// finds the slot of the local named `name`. pending functions are only visible to the closures
+// capturing them
+func (c *Compiler) resolveLocal(name string, includePending bool) int {
+	for i := len(c.locals) - 1; i >= 0; i-- {
+		if c.locals[i].name == name && (includePending || !c.locals[i].isPending) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// reserves a slot for every function declared directly within a block, so functions declared earlier
+// can call the ones declared later
+func (c *Compiler) declareFuncs(nodes []ast.AstNode) *CompilerError {
+	for _, node := range nodes {
+		declaration, ok := node.Value.(ast.FuncDeclarationStmt)
+		if !ok {
+			continue
+		}
+
+		c.span = declaration.Span
+		c.emitConstant(*runtime.NewRuntimeValue(&Undeclared{Name: declaration.Name}))
+
+		if err := c.addLocal(declaration.Name); err != nil {
+			return err
+		}
+
+		c.locals[len(c.locals)-1].isPending = true
+	}
+
+	return nil
+}
+
+func (c *Compiler) resolveUpvalue(name string) (int, *CompilerError) {
+	if c.enclosing == nil {
+		return -1, nil
+	}
+
+	if slot := c.enclosing.resolveLocal(name, true); slot != -1 {
+		c.enclosing.locals[slot].isCaptured = true
+		return c.addUpvalue(uint8(slot), true)
+	}
+
+	idx, err := c.enclosing.resolveUpvalue(name)
+	if err != nil || idx == -1 {
+		return idx, err
+	}
+
+	return c.addUpvalue(uint8(idx), false)
+}
+
+func (c *Compiler) addUpvalue(index uint8, isLocal bool) (int, *CompilerError) {
+	for i, upvalue := range c.upvalues {
+		if upvalue.index == index && upvalue.isLocal == isLocal {
+			return i, nil
+		}
+	}
+
+	if len(c.upvalues) >= MAX_LOCALS {
+		return -1, NewCompilerError(TOO_MANY_UPVALUES, "", c.span)
+	}
+
+	c.upvalues = append(c.upvalues, upvalue{index: index, isLocal: isLocal})
+	c.function.UpvalueCount = len(c.upvalues)
+
+	return len(c.upvalues) - 1, nil
+}
+
+func (c *Compiler) emitGetVar(name string) *CompilerError {
+	if slot := c.resolveLocal(name, false); slot != -1 {
+		c.emitU8(OP_GET_LOCAL, uint8(slot))
+		return nil
+	}
+
+	idx, err := c.resolveUpvalue(name)
+	if err != nil {
+		return err
+	}
+
+	if idx != -1 {
+		c.emitU8(OP_GET_UPVALUE, uint8(idx))
+		return nil
+	}
+
+	c.emitU16(OP_GET_GLOBAL, c.makeConstant(*runtime.NewRuntimeValue(name)))
+	return nil
+}
+
+// assigns the value on top of the stack to the variable, leaving the value on the stack
+func (c *Compiler) emitSetVar(name string) *CompilerError {
+	if slot := c.resolveLocal(name, false); slot != -1 {
+		c.emitU8(OP_SET_LOCAL, uint8(slot))
+		return nil
+	}
+
+	idx, err := c.resolveUpvalue(name)
+	if err != nil {
+		return err
+	}
+
+	if idx != -1 {
+		c.emitU8(OP_SET_UPVALUE, uint8(idx))
+		return nil
+	}
+
+	c.emitU16(OP_SET_GLOBAL, c.makeConstant(*runtime.NewRuntimeValue(name)))
+	return nil
+}
+
+// compiles a function body with a compiler of its own. `enclosing` is the compiler the function's
+// upvalues are resolved against, nil if it only closes over globals
+func (c *Compiler) compileFunction(enclosing *Compiler, name string, args []ast.AstNode, node ast.AstNode, span tokens.Span) (*Compiler, *CompilerError) {
+	fc := newCompiler(enclosing, NewFunction(name, len(args)))
+	fc.span = span
+	fc.beginScope()
+
+	for _, arg := range args {
+		if err := fc.addLocal(arg.Value.(ast.LiteralExpr).Value); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := fc.compileNode(node); err != nil {
+		return nil, err
+	}
+
+	// a function which ends without a `bussin` returns nada
+	fc.emit(OP_NIL)
+	fc.emit(OP_RETURN)
+
+	return fc, fc.err
+}
+
+// compiles a node whose value is needed, leaving exactly one value on the stack
+func (c *Compiler) compileValue(node ast.AstNode) *CompilerError {
+	switch v := node.Value.(type) {
+	case ast.Expr:
+		return c.compileExpr(v)
+	case ast.ReturnStmt:
+		return c.compileValue(v.Node)
+	default:
+		if err := c.compileNode(node); err != nil {
+			return err
+		}
+
+		c.emit(OP_NIL)
+		return nil
+	}
+}
+
+// compiles a node whose value isn't needed, leaving the stack as it was (apart from declared locals)
+func (c *Compiler) compileNode(node ast.AstNode) *CompilerError {
+	if expr, isExpr := node.Value.(ast.Expr); isExpr {
+		if err := c.compileExpr(expr); err != nil {
+			return err
+		}
+
+		c.emit(OP_POP)
+		return nil
+	}
+
+	stmt, isStmt := node.Value.(ast.Stmt)
+	if !isStmt {
+		return nil
+	}
+
+	prevSpan := c.span
+	c.span = stmt.GetSpan()
+	defer func() { c.span = prevSpan }()
+
+	switch v := stmt.(type) {
+	case ast.PrintStmt:
+		if err := c.compileValue(v.Node); err != nil {
+			return err
+		}
+
+		c.emit(OP_PRINT)
+	case ast.VarAssignStmt:
+		if _, isFunc := v.Node.Value.(ast.FuncExpr); isFunc && c.scopeDepth > 0 {
+			return c.compileLocalFunc(v)
+		}
+
+		if err := c.compileValue(v.Node); err != nil {
+			return err
+		}
+
+		if c.scopeDepth == 0 {
+			c.emitU16(OP_DEFINE_GLOBAL, c.makeConstant(*runtime.NewRuntimeValue(v.Name)))
+			return nil
+		}
+
+		return c.addLocal(v.Name)
+	case ast.CreateBlockStmt:
+		c.beginScope()
+
+		if err := c.declareFuncs(v.Nodes); err != nil {
+			return err
+		}
+
+		for _, node := range v.Nodes {
+			if err := c.compileNode(node); err != nil {
+				return err
+			}
+		}
+
+		c.endScope()
+	case ast.IfStmt:
+		return c.compileIf(v)
+	case ast.WhileStmt:
+		return c.compileLoop(v.Node, v.Branch, nil)
+	case ast.ForStmt:
+		// the variable declared by `init` lives in the enclosing scope, same as in the tree-walking runner
+		if err := c.compileNode(v.Init); err != nil {
+			return err
+		}
+
+		return c.compileLoop(v.Condition, v.Node, &v.Update)
+	case ast.ReturnStmt:
+		// returns from the enclosing function, a top-level `bussin` ends the program
+		if err := c.compileValue(v.Node); err != nil {
+			return err
+		}
+
+		c.emit(OP_RETURN)
+	case ast.BreakStmt, ast.ContinueStmt:
+		// the parser makes sure these only show up within a loop
+		l := c.loops[len(c.loops)-1]
+		c.discardLocals(l.scopeDepth)
+
+		if _, isBreak := v.(ast.BreakStmt); isBreak {
+			l.breakJumps = append(l.breakJumps, c.emitJump(OP_JUMP))
+		} else {
+			l.continueJumps = append(l.continueJumps, c.emitJump(OP_JUMP))
+		}
+	case ast.FuncDeclarationStmt:
+		return c.compileFuncDeclaration(v)
+	case ast.CloseBlockStmt:
+		// the scope of a block is ended by the block itself once its statements are compiled
+	}
+
+	return nil
+}
+
+// leaves the variable's new value on the stack for a prefix step, and its previous value otherwise
+func (c *Compiler) compileIncrement(increment ast.IncrementExpr) *CompilerError {
+	op := OP_INCREMENT
+	if increment.Operator == tokens.MINUS_MINUS {
+		op = OP_DECREMENT
+	}
+
+	if err := c.emitGetVar(increment.Name); err != nil {
+		return err
+	}
+
+	// a postfix step keeps a copy of the previous value below the new one, which is popped once it's
+	// assigned
+	if !increment.IsPrefix {
+		if err := c.emitGetVar(increment.Name); err != nil {
+			return err
+		}
+	}
+
+	c.emitU16(op, c.makeConstant(*runtime.NewRuntimeValue(increment.Name)))
+
+	if err := c.emitSetVar(increment.Name); err != nil {
+		return err
+	}
+
+	if !increment.IsPrefix {
+		c.emit(OP_POP)
+	}
+
+	return nil
+}
+
+func (c *Compiler) compileIf(ifStmt ast.IfStmt) *CompilerError {
+	branches := []ast.ElseIfStmt{ast.NewElseIfStmt(ifStmt.Node, ifStmt.IfBranch, ifStmt.Span)}
+	if ifStmt.ElseIfBranches != nil {
+		branches = append(branches, *ifStmt.ElseIfBranches...)
+	}
+
+	var endJumps []int
+
+	for _, branch := range branches {
+		if err := c.compileValue(branch.Node); err != nil {
+			return err
+		}
+
+		nextJump := c.emitConditionJump("boolean")
+
+		if err := c.compileNode(branch.Branch); err != nil {
+			return err
+		}
+
+		endJumps = append(endJumps, c.emitJump(OP_JUMP))
+
+		if err := c.patchJump(nextJump); err != nil {
+			return err
+		}
+	}
+
+	if ifStmt.ElseBranch != nil && ifStmt.ElseBranch.Branch.Value != nil {
+		if err := c.compileNode(ifStmt.ElseBranch.Branch); err != nil {
+			return err
+		}
+	}
+
+	for _, jump := range endJumps {
+		if err := c.patchJump(jump); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// compiles a `vibin` loop, or a `chillin` loop if `update` is set
+func (c *Compiler) compileLoop(condition ast.AstNode, body ast.AstNode, update *ast.AstNode) *CompilerError {
+	loopStart := len(c.chunk().Code)
+
+	if err := c.compileValue(condition); err != nil {
+		return err
+	}
+
+	exitJump := c.emitConditionJump("bool")
+
+	l := &loop{scopeDepth: c.scopeDepth}
+	c.loops = append(c.loops, l)
+
+	if err := c.compileNode(body); err != nil {
+		return err
+	}
+
+	c.loops = c.loops[:len(c.loops)-1]
+
+	// `skrrt` jumps to the update clause, which is followed by the condition
+	for _, jump := range l.continueJumps {
+		if err := c.patchJump(jump); err != nil {
+			return err
+		}
+	}
+
+	if update != nil {
+		if err := c.compileNode(*update); err != nil {
+			return err
+		}
+	}
+
+	if err := c.emitLoop(loopStart); err != nil {
+		return err
+	}
+
+	for _, jump := range append([]int{exitJump}, l.breakJumps...) {
+		if err := c.patchJump(jump); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// binds a named function once its declaration is reached. outside of any block it's a global,
+// otherwise it fills the slot reserved by `declareFuncs`
+func (c *Compiler) compileFuncDeclaration(declaration ast.FuncDeclarationStmt) *CompilerError {
+	slot := -1
+	for i := len(c.locals) - 1; i >= 0 && c.locals[i].depth == c.scopeDepth; i-- {
+		if c.locals[i].name == declaration.Name && c.locals[i].isPending {
+			slot = i
+			break
+		}
+	}
+
+	// the function can see itself, so it can be recursive
+	if slot != -1 {
+		c.locals[slot].isPending = false
+	}
+
+	fc, err := c.compileFunction(c, declaration.Name, declaration.Args, declaration.Node, declaration.Span)
+	if err != nil {
+		return err
+	}
+
+	c.emitClosure(fc.function, fc.upvalues)
+
+	if c.scopeDepth == 0 {
+		c.emitU16(OP_DEFINE_GLOBAL, c.makeConstant(*runtime.NewRuntimeValue(declaration.Name)))
+		return nil
+	}
+
+	if slot == -1 {
+		return c.addLocal(declaration.Name)
+	}
+
+	c.emitU8(OP_SET_LOCAL, uint8(slot))
+	c.emit(OP_POP)
+
+	return nil
+}
+
+// a local assigned a function expression gets its slot before the function is compiled, like the
+// ones reserved by `declareFuncs`, so the function can call itself through the variable
+func (c *Compiler) compileLocalFunc(assign ast.VarAssignStmt) *CompilerError {
+	c.emit(OP_NIL)
+
+	if err := c.addLocal(assign.Name); err != nil {
+		return err
+	}
+
+	slot := len(c.locals) - 1
+
+	if err := c.compileValue(assign.Node); err != nil {
+		return err
+	}
+
+	c.emitU8(OP_SET_LOCAL, uint8(slot))
+	c.emit(OP_POP)
+
+	return nil
+}
+
+// the callee is pushed first and then the arguments from left to right
+func (c *Compiler) compileCall(call ast.CallExpr) *CompilerError {
+	if err := c.compileValue(call.Callee); err != nil {
+		return err
+	}
+
+	for _, arg := range call.Args {
+		if err := c.compileValue(arg); err != nil {
+			return err
+		}
+	}
+
+	prevSpan := c.span
+	c.span = call.Span
+	c.emitU8(OP_CALL, uint8(len(call.Args)))
+	c.span = prevSpan
+
+	return nil
+}
+
+var binaryOps = map[tokens.TokenType]OpCode{
+	tokens.PLUS:            OP_ADD,
+	tokens.MINUS:           OP_SUBTRACT,
+	tokens.STAR:            OP_MULTIPLY,
+	tokens.SLASH:           OP_DIVIDE,
+	tokens.MODULO:          OP_MODULO,
+	tokens.STAR_STAR:       OP_POWER,
+	tokens.BIT_AND:         OP_BIT_AND,
+	tokens.BIT_OR:          OP_BIT_OR,
+	tokens.BIT_XOR:         OP_BIT_XOR,
+	tokens.LESS_LESS:       OP_SHIFT_LEFT,
+	tokens.GREATER_GREATER: OP_SHIFT_RIGHT,
+	tokens.LESS:            OP_LESS,
+	tokens.LESS_EQUAL:      OP_LESS_EQUAL,
+	tokens.GREATER:         OP_GREATER,
+	tokens.GREATER_EQUAL:   OP_GREATER_EQUAL,
+	tokens.EQUAL_EQUAL:     OP_EQUAL,
+	tokens.BANG_EQUAL:      OP_NOT_EQUAL,
+}
+
+// token types of the operators compiled to each binary opcode, used to report errors
+var BinaryOpOperators = func() map[OpCode]tokens.TokenType {
+	operators := make(map[OpCode]tokens.TokenType, len(binaryOps))
+	for operator, op := range binaryOps {
+		operators[op] = operator
+	}
+
+	return operators
+}()
+
+func (c *Compiler) compileExpr(expr ast.Expr) *CompilerError {
+	prevSpan := c.span
+	c.span = expr.GetSpan()
+	defer func() { c.span = prevSpan }()
+
+	switch v := expr.(type) {
+	case ast.LiteralExpr:
+		return c.compileLiteral(v)
+	case ast.GroupingExpr:
+		return c.compileValue(v.Node)
+	case ast.CallExpr:
+		return c.compileCall(v)
+	case ast.LogicalExpr:
+		if err := c.compileExpr(v.Left); err != nil {
+			return err
+		}
+
+		op := OP_AND
+		if v.Operator == tokens.OR {
+			op = OP_OR
+		}
+
+		endJump := c.emitJump(op)
+
+		if err := c.compileExpr(v.Right); err != nil {
+			return err
+		}
+
+		c.emitU8(OP_ASSERT_BOOL, uint8(v.Operator))
+
+		return c.patchJump(endJump)
+	case ast.UnaryExpr:
+		if err := c.compileExpr(v.Expr); err != nil {
+			return err
+		}
+
+		switch v.Operator {
+		case tokens.MINUS:
+			c.emit(OP_NEGATE)
+		case tokens.BIT_NOT:
+			c.emit(OP_BIT_NOT)
+		default:
+			c.emit(OP_NOT)
+		}
+	case ast.IncrementExpr:
+		return c.compileIncrement(v)
+	case ast.AssignExpr:
+		if err := c.compileExpr(v.Value); err != nil {
+			return err
+		}
+
+		return c.emitSetVar(v.Name)
+	case ast.IndexAssignExpr:
+		if err := c.compileValue(v.Target.Node); err != nil {
+			return err
+		}
+
+		if err := c.compileValue(v.Target.Index); err != nil {
+			return err
+		}
+
+		if err := c.compileExpr(v.Value); err != nil {
+			return err
+		}
+
+		c.emit(OP_SET_INDEX)
+	case ast.CompoundAssignExpr:
+		// the variable is read before the value is evaluated, same as in the tree-walking evaluator
+		if err := c.emitGetVar(v.Name); err != nil {
+			return err
+		}
+
+		if err := c.compileExpr(v.Value); err != nil {
+			return err
+		}
+
+		c.emit(binaryOps[v.Operator])
+
+		return c.emitSetVar(v.Name)
+	case ast.BinaryExpr:
+		if err := c.compileExpr(v.Left); err != nil {
+			return err
+		}
+
+		if err := c.compileExpr(v.Right); err != nil {
+			return err
+		}
+
+		op, ok := binaryOps[v.Operator]
+		if !ok {
+			return NewCompilerError(runtime.INVALID_OPERATOR, v.Operator.Literal(), v.Span)
+		}
+
+		c.emit(op)
+	case ast.FuncExpr:
+		// anonymous functions can capture the locals of the functions they are nested in
+		fc, err := c.compileFunction(c, "", v.Args, v.Node, v.Span)
+		if err != nil {
+			return err
+		}
+
+		c.emitClosure(fc.function, fc.upvalues)
+	case ast.ListExpr:
+		for _, element := range v.Elements {
+			if err := c.compileValue(element); err != nil {
+				return err
+			}
+		}
+
+		c.emitU16(OP_LIST, uint16(len(v.Elements)))
+	case ast.MapExpr:
+		for _, entry := range v.Entries {
+			if err := c.compileValue(entry.Key); err != nil {
+				return err
+			}
+
+			if err := c.compileValue(entry.Value); err != nil {
+				return err
+			}
+		}
+
+		c.emitU16(OP_MAP, uint16(len(v.Entries)))
+	case ast.IndexExpr:
+		if err := c.compileValue(v.Node); err != nil {
+			return err
+		}
+
+		if err := c.compileValue(v.Index); err != nil {
+			return err
+		}
+
+		c.emit(OP_INDEX)
+	case ast.InterpolatedStrExpr:
+		for _, part := range v.Parts {
+			if err := c.compileValue(part); err != nil {
+				return err
+			}
+		}
+
+		c.emitU16(OP_BUILD_STRING, uint16(len(v.Parts)))
+	default:
+		c.emit(OP_NIL)
+	}
+
+	return nil
+}
+
+func (c *Compiler) compileLiteral(literal ast.LiteralExpr) *CompilerError {
+	switch literal.TokenType {
+	case tokens.STRING:
+		c.emitConstant(*runtime.NewRuntimeValue(literal.Value))
+	case tokens.NUMBER:
+		num, err := runtime.ParseNumber(literal.Value)
+		if err != nil {
+			return NewCompilerError(INVALID_NUMBER, literal.Value, literal.Span)
+		}
+
+		c.emitConstant(num)
+	case tokens.TRUE:
+		c.emit(OP_TRUE)
+	case tokens.FALSE:
+		c.emit(OP_FALSE)
+	case tokens.IDENTIFIER:
+		return c.emitGetVar(literal.Value)
+	default:
+		c.emit(OP_NIL)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package compiler
+
+import (
+	"fmt"
+
+	"github.com/0xmukesh/interpreter/internal/diagnostics"
+	"github.com/0xmukesh/interpreter/internal/tokens"
+)
+
+var (
+	TOO_MANY_LOCALS    = diagnostics.Message{Code: "C001", Text: "bro really declared more than 256 variables in one skibidi"}
+	TOO_MANY_CONSTANTS = diagnostics.Message{Code: "C002", Text: "bro really needs more than 65536 constants in one skibidi"}
+	TOO_MANY_UPVALUES  = diagnostics.Message{Code: "C003", Text: "bro really captured more than 256 variables in one skibidi"}
+	JUMP_TOO_LONG      = diagnostics.Message{Code: "C004", Text: "this jump is way too long, fam. split the code up a bit"}
+	INVALID_NUMBER     = diagnostics.Message{Code: "C005", Text: "that number ain't it, chief"}
+
+	IDENTIFIER_ALREADY_EXISTS = diagnostics.Message{Code: "C006", Text: "nah, the sequel ain't happening for this identifier"}
+)
+
+type CompilerError struct {
+	Code    string
+	Message string
+	At      string
+	Span    tokens.Span
+	// name of the file being compiled, it's empty when the source doesn't come from a file
+	File string
+}
+
+func NewCompilerError(msg diagnostics.Message, at string, span tokens.Span) *CompilerError {
+	return &CompilerError{
+		Code:    msg.Code,
+		Message: msg.Text,
+		At:      at,
+		Span:    span,
+	}
+}
+
+func (e CompilerError) label() string {
+	return fmt.Sprintf("hell naw, im done with you. you caused a compiler error at '%s'", e.At)
+}
+
+func (e CompilerError) Error() string {
+	return fmt.Sprintf("[%s] %s: %s", e.Span.Start.Format(e.File), e.label(), e.Message)
+}
+
+func (e CompilerError) Diagnostic() diagnostics.Diagnostic {
+	return diagnostics.Diagnostic{
+		Severity: diagnostics.ERROR,
+		Code:     e.Code,
+		Message:  e.Message,
+		Label:    e.label(),
+		Span:     e.Span,
+	}
+}
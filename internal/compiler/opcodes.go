@@ -0,0 +1,83 @@
+package compiler
+
+type OpCode byte
+
+// operands follow the opcode in the chunk. u8 operands take one byte and u16 operands take two bytes
+// (big endian)
+const (
+	// u16 constant index
+	OP_CONSTANT OpCode = iota
+	OP_NIL
+	OP_TRUE
+	OP_FALSE
+	OP_POP
+
+	// u8 stack slot, relative to the current frame
+	OP_GET_LOCAL
+	OP_SET_LOCAL
+	// u8 index into the closure's upvalues
+	OP_GET_UPVALUE
+	OP_SET_UPVALUE
+	// u16 constant index of the variable's name
+	OP_GET_GLOBAL
+	OP_DEFINE_GLOBAL
+	OP_SET_GLOBAL
+
+	OP_ADD
+	OP_SUBTRACT
+	OP_MULTIPLY
+	OP_DIVIDE
+	OP_MODULO
+	OP_POWER
+	OP_BIT_AND
+	OP_BIT_OR
+	OP_BIT_XOR
+	OP_SHIFT_LEFT
+	OP_SHIFT_RIGHT
+	OP_LESS
+	OP_LESS_EQUAL
+	OP_GREATER
+	OP_GREATER_EQUAL
+	OP_EQUAL
+	OP_NOT_EQUAL
+	OP_NEGATE
+	OP_NOT
+	OP_BIT_NOT
+
+	// u16 forward jump, taken (keeping the left operand) if the left operand decides the result
+	OP_AND
+	OP_OR
+	// u8 operator token type, checks the right operand of `&&` or `||`
+	OP_ASSERT_BOOL
+
+	// u16 constant index of the variable's name, used in error messages
+	OP_INCREMENT
+	OP_DECREMENT
+
+	OP_PRINT
+
+	// u16 forward jump
+	OP_JUMP
+	// u16 forward jump followed by the u16 constant index of the kind of expression the condition
+	// is reported as not being if it isn't a bool, pops the condition
+	OP_JUMP_IF_FALSE
+	// u16 backward jump
+	OP_LOOP
+
+	// u8 arguments count
+	OP_CALL
+	// u16 constant index of the function, followed by an (u8 is local, u8 index) pair per upvalue
+	OP_CLOSURE
+	OP_CLOSE_UPVALUE
+	OP_RETURN
+
+	// u16 elements count
+	OP_LIST
+	// u16 entries count
+	OP_MAP
+	OP_INDEX
+	OP_SET_INDEX
+
+	// u16 parts count, joins the parts of an interpolated string into a string
+	OP_BUILD_STRING
+)
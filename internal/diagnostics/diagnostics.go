@@ -0,0 +1,205 @@
+// Package diagnostics renders the errors and warnings of every stage of the interpreter along with
+// the part of the source they point at
+package diagnostics
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/0xmukesh/interpreter/internal/tokens"
+)
+
+type Severity int
+
+const (
+	ERROR Severity = iota
+	WARNING
+)
+
+func (s Severity) String() string {
+	if s == WARNING {
+		return "warning"
+	}
+
+	return "error"
+}
+
+// Note is extra context about a diagnostic, such as where a variable was declared. `Span` is nil
+// for notes which don't point at the source
+type Note struct {
+	Message string
+	Span    *tokens.Span
+}
+
+// Diagnostic is an error or a warning which can be rendered along with the source it points at
+type Diagnostic struct {
+	Severity Severity
+	Code     string
+	// what went wrong, shown in the header
+	Message string
+	// shown next to the underlined source
+	Label string
+	Span  tokens.Span
+	Notes []Note
+}
+
+// Diagnosable is implemented by the errors of every stage of the interpreter
+type Diagnosable interface {
+	error
+	Diagnostic() Diagnostic
+}
+
+// Message is the text of an error along with its code. it's an error itself, so helpers which don't
+// know where an error happened can return one
+type Message struct {
+	Code string
+	Text string
+}
+
+func (m Message) Error() string {
+	return m.Text
+}
+
+// With fills the verbs of a message template with `args`, keeping its code
+func (m Message) With(args ...any) Message {
+	return Message{Code: m.Code, Text: fmt.Sprintf(m.Text, args...)}
+}
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiRed    = "\x1b[1;31m"
+	ansiYellow = "\x1b[1;33m"
+	ansiBlue   = "\x1b[1;34m"
+	ansiCyan   = "\x1b[1;36m"
+)
+
+// Renderer renders diagnostics of a single source, like
+//
+//	error[P006]: nahh, you left me hanging. where's ';' at?
+//	 --> main.brt:1:10
+//	  |
+//	1 | rizz a = 1
+//	  |          ^ hell naw, im done with you. you caused a parser error at '1'
+type Renderer struct {
+	Src []byte
+	// name of the file the source comes from, it's empty when the source doesn't come from a file
+	File string
+	// colours the output with ansi escape codes, meant for terminals
+	Color bool
+}
+
+func NewRenderer(src []byte, file string, color bool) *Renderer {
+	return &Renderer{
+		Src:   src,
+		File:  file,
+		Color: color,
+	}
+}
+
+func (r *Renderer) paint(color string, s string) string {
+	if !r.Color {
+		return s
+	}
+
+	return color + s + ansiReset
+}
+
+func (r *Renderer) Render(d Diagnostic) string {
+	var b strings.Builder
+
+	severityColor := ansiRed
+	if d.Severity == WARNING {
+		severityColor = ansiYellow
+	}
+
+	// every snippet shares the gutter, so it's as wide as the largest line number shown
+	gutterWidth := len(fmt.Sprint(d.Span.Start.Line))
+	for _, note := range d.Notes {
+		if note.Span != nil {
+			gutterWidth = max(gutterWidth, len(fmt.Sprint(note.Span.Start.Line)))
+		}
+	}
+
+	header := d.Severity.String()
+	if d.Code != "" {
+		header += "[" + d.Code + "]"
+	}
+
+	fmt.Fprintf(&b, "%s%s\n", r.paint(severityColor, header), r.paint(ansiBold, ": "+d.Message))
+	r.renderSnippet(&b, d.Span, gutterWidth, "^", severityColor, d.Label)
+
+	for _, note := range d.Notes {
+		fmt.Fprintf(&b, "%s %s %s\n", strings.Repeat(" ", gutterWidth), r.paint(ansiBlue, "="), r.paint(ansiBold, "note: ")+note.Message)
+
+		if note.Span != nil {
+			r.renderSnippet(&b, *note.Span, gutterWidth, "-", ansiCyan, "")
+		}
+	}
+
+	return b.String()
+}
+
+// writes the location of `span` followed by its first line, with the spanned part underlined
+func (r *Renderer) renderSnippet(b *strings.Builder, span tokens.Span, gutterWidth int, underline string, color string, label string) {
+	pad := strings.Repeat(" ", gutterWidth)
+	bar := r.paint(ansiBlue, "|")
+
+	fmt.Fprintf(b, "%s%s %s\n", pad, r.paint(ansiBlue, "-->"), span.Start.Format(r.File))
+
+	line, ok := r.line(span.Start)
+	if !ok {
+		return
+	}
+
+	lineNumber := fmt.Sprintf("%*d", gutterWidth, span.Start.Line)
+
+	col := min(max(span.Start.Column-1, 0), len(line))
+
+	// the width is counted in characters rather than bytes, like the indent below
+	width := utf8.RuneCountInString(line[col:])
+	if span.End.Line == span.Start.Line && span.Start.Offset <= span.End.Offset && span.End.Offset <= len(r.Src) {
+		width = utf8.RuneCount(r.Src[span.Start.Offset:span.End.Offset])
+	}
+
+	width = max(width, 1)
+
+	// tabs are kept so the underline lines up with the source however wide tabs are rendered
+	var indent strings.Builder
+	for _, char := range line[:col] {
+		if char == '\t' {
+			indent.WriteRune('\t')
+		} else {
+			indent.WriteRune(' ')
+		}
+	}
+
+	marker := r.paint(color, strings.Repeat(underline, width))
+	if label != "" {
+		marker += " " + r.paint(color, label)
+	}
+
+	fmt.Fprintf(b, "%s %s\n", pad, bar)
+	fmt.Fprintf(b, "%s %s %s\n", r.paint(ansiBlue, lineNumber), bar, line)
+	fmt.Fprintf(b, "%s %s %s%s\n", pad, bar, indent.String(), marker)
+}
+
+// returns the line of the source `pos` is on, without its line break
+func (r *Renderer) line(pos tokens.Position) (string, bool) {
+	if pos.Offset < 0 || pos.Offset > len(r.Src) {
+		return "", false
+	}
+
+	start := pos.Offset
+	for start > 0 && r.Src[start-1] != '\n' {
+		start--
+	}
+
+	end := pos.Offset
+	for end < len(r.Src) && r.Src[end] != '\n' {
+		end++
+	}
+
+	return strings.TrimSuffix(string(r.Src[start:end]), "\r"), true
+}
@@ -0,0 +1,105 @@
+package diagnostics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/0xmukesh/interpreter/internal/tokens"
+)
+
+// span of `length` bytes starting at byte `offset` of `src`
+func spanAt(src string, offset int, length int) tokens.Span {
+	pos := func(offset int) tokens.Position {
+		p := tokens.Position{Offset: offset, Line: 1, Column: 1}
+		for _, char := range []byte(src[:offset]) {
+			if char == '\n' {
+				p.Line++
+				p.Column = 1
+			} else {
+				p.Column++
+			}
+		}
+
+		return p
+	}
+
+	return tokens.Span{Start: pos(offset), End: pos(offset + length)}
+}
+
+func TestRender(t *testing.T) {
+	src := "rizz a = 1;\n\trizz a = 2;\n"
+	first := spanAt(src, 5, 1)
+
+	d := Diagnostic{
+		Severity: ERROR,
+		Code:     "R002",
+		Message:  "nah, the sequel ain't happening for this identifier",
+		Label:    "declared again",
+		Span:     spanAt(src, 18, 1),
+		Notes: []Note{
+			{Message: "it's first declared here", Span: &first},
+			{Message: "rename one of them"},
+		},
+	}
+
+	expected := "error[R002]: nah, the sequel ain't happening for this identifier\n" +
+		" --> main.brt:2:7\n" +
+		"  |\n" +
+		"2 | \trizz a = 2;\n" +
+		"  | \t     ^ declared again\n" +
+		"  = note: it's first declared here\n" +
+		" --> main.brt:1:6\n" +
+		"  |\n" +
+		"1 | rizz a = 1;\n" +
+		"  |      -\n" +
+		"  = note: rename one of them\n"
+
+	if got := NewRenderer([]byte(src), "main.brt", false).Render(d); got != expected {
+		t.Errorf("got\n%s\nexpected\n%s", got, expected)
+	}
+}
+
+func TestRenderSpanningLines(t *testing.T) {
+	src := "yap(\"abc\n  def\");"
+
+	d := Diagnostic{Severity: WARNING, Message: "sus", Span: spanAt(src, 4, 12)}
+
+	expected := "warning: sus\n" +
+		" --> 1:5\n" +
+		"  |\n" +
+		"1 | yap(\"abc\n" +
+		"  |     ^^^^\n"
+
+	if got := NewRenderer([]byte(src), "", false).Render(d); got != expected {
+		t.Errorf("got\n%s\nexpected\n%s", got, expected)
+	}
+}
+
+func TestRenderUnicode(t *testing.T) {
+	src := `yap("ééé" + 1);`
+
+	d := Diagnostic{Severity: ERROR, Code: "E014", Message: "wildin'", Span: spanAt(src, 4, len(`"ééé" + 1`))}
+
+	expected := "error[E014]: wildin'\n" +
+		" --> 1:5\n" +
+		"  |\n" +
+		"1 | yap(\"ééé\" + 1);\n" +
+		"  |     ^^^^^^^^^\n"
+
+	if got := NewRenderer([]byte(src), "", false).Render(d); got != expected {
+		t.Errorf("got\n%s\nexpected\n%s", got, expected)
+	}
+}
+
+func TestRenderColor(t *testing.T) {
+	src := "yap(1 + cap);"
+	d := Diagnostic{Severity: ERROR, Code: "E014", Message: "wildin'", Span: spanAt(src, 4, 7)}
+
+	if plain := NewRenderer([]byte(src), "", false).Render(d); strings.Contains(plain, "\x1b[") {
+		t.Errorf("expected no escape codes without the colour mode, got %q", plain)
+	}
+
+	if colored := NewRenderer([]byte(src), "", true).Render(d); !strings.Contains(colored, "\x1b[1;31merror[E014]") {
+		t.Errorf("expected the header to be red, got %q", colored)
+	}
+}
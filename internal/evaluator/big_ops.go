@@ -0,0 +1,171 @@
+package evaluator
+
+import (
+	"math/big"
+
+	"github.com/0xmukesh/interpreter/internal/runtime"
+	"github.com/0xmukesh/interpreter/internal/tokens"
+)
+
+// about the most bits a big int made by `<<` or `**` can have, so a script can't use up all the memory
+const MAX_BIG_INT_BITS = 1 << 22
+
+// reports whether the value is a big int or a decimal
+func isBigNumber(val runtime.RuntimeValue) bool {
+	switch val.Value.(type) {
+	case *big.Int, *runtime.Decimal:
+		return true
+	default:
+		return false
+	}
+}
+
+// applies a binary operator to operands of which at least one is a big int or a decimal. they are
+// never converted implicitly, so both operands have to be of the same type
+func bigNumberOp(operator tokens.TokenType, left runtime.RuntimeValue, right runtime.RuntimeValue, span tokens.Span) (*runtime.RuntimeValue, *runtime.RuntimeError) {
+	isEquality := operator == tokens.EQUAL_EQUAL || operator == tokens.BANG_EQUAL
+
+	if !(runtime.IsNumber(left) && runtime.IsNumber(right)) {
+		if isEquality {
+			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("same"), operator.Literal(), span)
+		}
+
+		return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("number"), operator.Literal(), span)
+	}
+
+	if left.TypeName() != right.TypeName() {
+		return nil, runtime.NewRuntimeError(runtime.MixedNumbersErrBuilder(left, right), operator.Literal(), span).WithNote(runtime.CONVERT_NUMBERS_NOTE, nil)
+	}
+
+	if isIntegerOperator(operator) {
+		l, isLeftBigInt := left.Value.(*big.Int)
+		if !isLeftBigInt {
+			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("int"), operator.Literal(), span)
+		}
+
+		return bigIntegerOp(operator, l, right.Value.(*big.Int), span)
+	}
+
+	var cmp int
+
+	switch l := left.Value.(type) {
+	case *big.Int:
+		r := right.Value.(*big.Int)
+
+		if (operator == tokens.SLASH || operator == tokens.MODULO) && r.Sign() == 0 {
+			return nil, runtime.NewRuntimeError(runtime.DIVIDE_BY_ZERO, operator.Literal(), span)
+		}
+
+		// like ints, `/` drops the fraction and `%` has the sign of the left operand
+		switch operator {
+		case tokens.PLUS:
+			return runtime.NewRuntimeValue(new(big.Int).Add(l, r)), nil
+		case tokens.MINUS:
+			return runtime.NewRuntimeValue(new(big.Int).Sub(l, r)), nil
+		case tokens.STAR:
+			return runtime.NewRuntimeValue(new(big.Int).Mul(l, r)), nil
+		case tokens.SLASH:
+			return runtime.NewRuntimeValue(new(big.Int).Quo(l, r)), nil
+		case tokens.MODULO:
+			return runtime.NewRuntimeValue(new(big.Int).Rem(l, r)), nil
+		}
+
+		cmp = l.Cmp(r)
+	case *runtime.Decimal:
+		r := right.Value.(*runtime.Decimal)
+
+		if (operator == tokens.SLASH || operator == tokens.MODULO) && r.Sign() == 0 {
+			return nil, runtime.NewRuntimeError(runtime.DIVIDE_BY_ZERO, operator.Literal(), span)
+		}
+
+		switch operator {
+		case tokens.PLUS:
+			return runtime.NewRuntimeValue(l.Add(r)), nil
+		case tokens.MINUS:
+			return runtime.NewRuntimeValue(l.Sub(r)), nil
+		case tokens.STAR:
+			return runtime.NewRuntimeValue(l.Mul(r)), nil
+		case tokens.SLASH:
+			return runtime.NewRuntimeValue(l.Quo(r)), nil
+		case tokens.MODULO:
+			return runtime.NewRuntimeValue(l.Rem(r)), nil
+		}
+
+		cmp = l.Cmp(r)
+	}
+
+	switch operator {
+	case tokens.LESS:
+		return runtime.NewRuntimeValue(cmp < 0), nil
+	case tokens.LESS_EQUAL:
+		return runtime.NewRuntimeValue(cmp <= 0), nil
+	case tokens.GREATER:
+		return runtime.NewRuntimeValue(cmp > 0), nil
+	case tokens.GREATER_EQUAL:
+		return runtime.NewRuntimeValue(cmp >= 0), nil
+	case tokens.EQUAL_EQUAL:
+		return runtime.NewRuntimeValue(cmp == 0), nil
+	case tokens.BANG_EQUAL:
+		return runtime.NewRuntimeValue(cmp != 0), nil
+	default:
+		return nil, runtime.NewRuntimeError(runtime.INVALID_OPERATOR, operator.Literal(), span)
+	}
+}
+
+// reports whether the operator only applies to ints, and big ints
+func isIntegerOperator(operator tokens.TokenType) bool {
+	switch operator {
+	case tokens.BIT_AND, tokens.BIT_OR, tokens.BIT_XOR, tokens.LESS_LESS, tokens.GREATER_GREATER, tokens.STAR_STAR:
+		return true
+	default:
+		return false
+	}
+}
+
+// applies `&`, `|`, `^`, `<<`, `>>` or `**` to big ints. `&`, `|` and `^` work on the two's
+// complement of negative big ints, like they do on ints
+func bigIntegerOp(operator tokens.TokenType, left *big.Int, right *big.Int, span tokens.Span) (*runtime.RuntimeValue, *runtime.RuntimeError) {
+	switch operator {
+	case tokens.BIT_AND:
+		return runtime.NewRuntimeValue(new(big.Int).And(left, right)), nil
+	case tokens.BIT_OR:
+		return runtime.NewRuntimeValue(new(big.Int).Or(left, right)), nil
+	case tokens.BIT_XOR:
+		return runtime.NewRuntimeValue(new(big.Int).Xor(left, right)), nil
+	case tokens.LESS_LESS, tokens.GREATER_GREATER:
+		if right.Sign() < 0 {
+			return nil, runtime.NewRuntimeError(runtime.NEGATIVE_SHIFT, operator.Literal(), span)
+		}
+
+		if operator == tokens.GREATER_GREATER {
+			// shifting every bit out leaves 0, or -1 for negative numbers, same as `>>` on ints
+			if !right.IsUint64() || right.Uint64() > uint64(left.BitLen()) {
+				return runtime.NewRuntimeValue(big.NewInt(int64(min(left.Sign(), 0)))), nil
+			}
+
+			return runtime.NewRuntimeValue(new(big.Int).Rsh(left, uint(right.Uint64()))), nil
+		}
+
+		if left.Sign() == 0 {
+			return runtime.NewRuntimeValue(new(big.Int)), nil
+		}
+
+		if !right.IsInt64() || right.Int64() > int64(MAX_BIG_INT_BITS-left.BitLen()) {
+			return nil, runtime.NewRuntimeError(runtime.BIG_INT_TOO_LARGE, operator.Literal(), span)
+		}
+
+		return runtime.NewRuntimeValue(new(big.Int).Lsh(left, uint(right.Int64()))), nil
+	default:
+		if right.Sign() < 0 {
+			return nil, runtime.NewRuntimeError(runtime.NEGATIVE_EXPONENT, operator.Literal(), span)
+		}
+
+		// 0, 1 and -1 stay as small whatever the exponent is. any other base has at least
+		// (BitLen - 1) * exponent bits once it's raised
+		if left.CmpAbs(big.NewInt(1)) > 0 && (!right.IsInt64() || right.Int64() > int64(MAX_BIG_INT_BITS/(left.BitLen()-1))) {
+			return nil, runtime.NewRuntimeError(runtime.BIG_INT_TOO_LARGE, operator.Literal(), span)
+		}
+
+		return runtime.NewRuntimeValue(new(big.Int).Exp(left, right, nil)), nil
+	}
+}
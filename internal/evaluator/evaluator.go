@@ -1,18 +1,23 @@
 package evaluator
 
 import (
-	"math"
-	"reflect"
-	"strconv"
+	"strings"
 
 	"github.com/0xmukesh/interpreter/internal/ast"
 	"github.com/0xmukesh/interpreter/internal/runtime"
 	"github.com/0xmukesh/interpreter/internal/tokens"
 )
 
+// FuncRunner runs the body of a called function, which is made up of statements rather than
+// expressions
+type FuncRunner interface {
+	RunFunc(function *runtime.Function, args []runtime.RuntimeValue, span tokens.Span) (*runtime.RuntimeValue, *runtime.RuntimeError)
+}
+
 type Evaluator struct {
 	Ast     ast.Ast
 	Runtime *runtime.Runtime
+	Runner  FuncRunner
 	Idx     int
 }
 
@@ -83,6 +88,26 @@ func (e *Evaluator) EvaluateExpr(expr ast.Expr) (*runtime.RuntimeValue, *runtime
 		return e.evaluteUnaryExpr(v)
 	case ast.BinaryExpr:
 		return e.evaluateBinaryExpr(v)
+	case ast.FuncExpr:
+		return e.evaluateFuncExpr(v)
+	case ast.CallExpr:
+		return e.evaluateCallExpr(v)
+	case ast.ListExpr:
+		return e.evaluateListExpr(v)
+	case ast.MapExpr:
+		return e.evaluateMapExpr(v)
+	case ast.IndexExpr:
+		return e.evaluateIndexExpr(v)
+	case ast.InterpolatedStrExpr:
+		return e.evaluateInterpolatedStrExpr(v)
+	case ast.IncrementExpr:
+		return e.evaluateIncrementExpr(v)
+	case ast.CompoundAssignExpr:
+		return e.evaluateCompoundAssignExpr(v)
+	case ast.AssignExpr:
+		return e.evaluateAssignExpr(v)
+	case ast.IndexAssignExpr:
+		return e.evaluateIndexAssignExpr(v)
 	default:
 		return nil, nil
 	}
@@ -93,12 +118,12 @@ func (e *Evaluator) evaluteLiteralExpr(literalExpr ast.LiteralExpr) (*runtime.Ru
 	case tokens.STRING:
 		return runtime.NewRuntimeValue(literalExpr.Value), nil
 	case tokens.NUMBER:
-		num, err := strconv.ParseFloat(literalExpr.Value, 64)
+		num, err := runtime.ParseNumber(literalExpr.Value)
 		if err != nil {
-			return nil, runtime.NewRuntimeError(err.Error(), literalExpr.Value, literalExpr.Line)
+			return nil, runtime.NewRuntimeError(runtime.MessageOf(err), literalExpr.Value, literalExpr.Span)
 		}
 
-		return runtime.NewRuntimeValue(num), nil
+		return &num, nil
 	case tokens.TRUE:
 		return runtime.NewRuntimeValue(true), nil
 	case tokens.FALSE:
@@ -106,58 +131,274 @@ func (e *Evaluator) evaluteLiteralExpr(literalExpr ast.LiteralExpr) (*runtime.Ru
 	case tokens.NIL:
 		return runtime.NewRuntimeValue(nil), nil
 	case tokens.IDENTIFIER:
-		currEnv := e.Runtime.CurrEnv()
-		val, _ := currEnv.GetVar(literalExpr.Value)
-
-		if val != nil {
+		if val := e.GetVar(literalExpr.Value, *literalExpr.Binding); val != nil {
 			return runtime.NewRuntimeValue(val.Value), nil
 		}
 
-		return nil, runtime.NewRuntimeError(runtime.UNDEFINED_IDENTIFIER, literalExpr.Value, literalExpr.Line)
+		if nativeFn, ok := e.Runtime.GetNativeFn(literalExpr.Value); ok {
+			return runtime.NewRuntimeValue(nativeFn), nil
+		}
+
+		return nil, runtime.NewRuntimeError(runtime.UNDEFINED_IDENTIFIER, literalExpr.Value, literalExpr.Span)
 	default:
 		return nil, nil
 	}
 }
 
+// returns the variable the resolver bound `name` to, nil if it isn't declared (yet)
+func (e *Evaluator) GetVar(name string, binding ast.Binding) *runtime.RuntimeValue {
+	if binding.IsGlobal() {
+		val, ok := e.Runtime.GlobalEnv().Vars[name]
+		if !ok {
+			return nil
+		}
+
+		return &val
+	}
+
+	return e.Runtime.CurrEnv().GetAt(binding.Depth, binding.Slot)
+}
+
+// assigns to the variable the resolver bound `name` to, reports false if it isn't declared (yet)
+func (e *Evaluator) SetVar(name string, binding ast.Binding, val runtime.RuntimeValue) bool {
+	if binding.IsGlobal() {
+		globalEnv := e.Runtime.GlobalEnv()
+		if _, ok := globalEnv.Vars[name]; !ok {
+			return false
+		}
+
+		globalEnv.SetVar(name, val)
+		return true
+	}
+
+	return e.Runtime.CurrEnv().SetAt(binding.Depth, binding.Slot, val)
+}
+
+// declares the variable `name` in the current environment, reports false if it's already declared
+func (e *Evaluator) DefineVar(name string, binding ast.Binding, val runtime.RuntimeValue) bool {
+	if binding.IsGlobal() {
+		globalEnv := e.Runtime.GlobalEnv()
+		if _, ok := globalEnv.Vars[name]; ok {
+			return false
+		}
+
+		globalEnv.SetVar(name, val)
+		return true
+	}
+
+	return e.Runtime.CurrEnv().DefineAt(binding.Slot, val)
+}
+
+// creates a function value which closes over the current environment
+func (e *Evaluator) evaluateFuncExpr(funcExpr ast.FuncExpr) (*runtime.RuntimeValue, *runtime.RuntimeError) {
+	return runtime.NewRuntimeValue(runtime.NewFunction("", funcExpr.Args, funcExpr.Node, e.Runtime.CurrEnv())), nil
+}
+
+// the callee is evaluated first and then the arguments from left to right, the call is only made
+// once all of them are evaluated. functions can only be run if a runner is attached, otherwise they
+// evaluate to nada
+func (e *Evaluator) evaluateCallExpr(callExpr ast.CallExpr) (*runtime.RuntimeValue, *runtime.RuntimeError) {
+	callee, err := e.EvaluateExpr(callExpr.Callee.ExtractExpr())
+	if err != nil {
+		return nil, err
+	}
+
+	if callee == nil {
+		callee = runtime.NewRuntimeValue(nil)
+	}
+
+	args := make([]runtime.RuntimeValue, 0, len(callExpr.Args))
+
+	for _, node := range callExpr.Args {
+		val, err := e.EvaluateExpr(node.ExtractExpr())
+		if err != nil {
+			return nil, err
+		}
+
+		if val == nil {
+			val = runtime.NewRuntimeValue(nil)
+		}
+
+		args = append(args, *val)
+	}
+
+	switch fn := callee.Value.(type) {
+	case *runtime.Function:
+		if len(args) != len(fn.Args) {
+			return nil, runtime.NewRuntimeError(runtime.ArgumentsCountErrBuilder(len(fn.Args), len(args)), fn.String(), callExpr.Span)
+		}
+
+		if e.Runner == nil {
+			return runtime.NewRuntimeValue(nil), nil
+		}
+
+		return e.Runner.RunFunc(fn, args, callExpr.Span)
+	case *runtime.NativeFnMapping:
+		if fn.Arity >= 0 && len(args) != fn.Arity {
+			return nil, runtime.NewRuntimeError(runtime.ArgumentsCountErrBuilder(fn.Arity, len(args)), fn.Name, callExpr.Span)
+		}
+
+		val, nativeErr := fn.Fn(args)
+		if nativeErr != nil {
+			return nil, runtime.NewRuntimeError(runtime.MessageOf(nativeErr), fn.Name, callExpr.Span)
+		}
+
+		val = runtime.FromGo(val.Value)
+		return &val, nil
+	default:
+		return nil, runtime.NewRuntimeError(runtime.NOT_CALLABLE, callee.String(), callExpr.Span)
+	}
+}
+
+func (e *Evaluator) evaluateListExpr(listExpr ast.ListExpr) (*runtime.RuntimeValue, *runtime.RuntimeError) {
+	elements := make([]runtime.RuntimeValue, 0, len(listExpr.Elements))
+
+	for _, node := range listExpr.Elements {
+		val, err := e.EvaluateExpr(node.ExtractExpr())
+		if err != nil {
+			return nil, err
+		}
+
+		if val == nil {
+			val = runtime.NewRuntimeValue(nil)
+		}
+
+		elements = append(elements, *val)
+	}
+
+	return runtime.NewRuntimeValue(runtime.NewList(elements)), nil
+}
+
+func (e *Evaluator) evaluateMapExpr(mapExpr ast.MapExpr) (*runtime.RuntimeValue, *runtime.RuntimeError) {
+	m := runtime.NewMap()
+
+	for _, entry := range mapExpr.Entries {
+		key, err := e.EvaluateExpr(entry.Key.ExtractExpr())
+		if err != nil {
+			return nil, err
+		}
+
+		if key == nil {
+			key = runtime.NewRuntimeValue(nil)
+		}
+
+		if keyErr := runtime.ValidateMapKey(*key); keyErr != nil {
+			return nil, runtime.NewRuntimeError(runtime.MessageOf(keyErr), key.String(), mapExpr.Span)
+		}
+
+		val, err := e.EvaluateExpr(entry.Value.ExtractExpr())
+		if err != nil {
+			return nil, err
+		}
+
+		if val == nil {
+			val = runtime.NewRuntimeValue(nil)
+		}
+
+		m.Set(*key, *val)
+	}
+
+	return runtime.NewRuntimeValue(m), nil
+}
+
+func (e *Evaluator) evaluateInterpolatedStrExpr(interpolatedStrExpr ast.InterpolatedStrExpr) (*runtime.RuntimeValue, *runtime.RuntimeError) {
+	var b strings.Builder
+
+	for _, node := range interpolatedStrExpr.Parts {
+		val, err := e.EvaluateExpr(node.ExtractExpr())
+		if err != nil {
+			return nil, err
+		}
+
+		if val == nil {
+			val = runtime.NewRuntimeValue(nil)
+		}
+
+		b.WriteString(val.String())
+	}
+
+	return runtime.NewRuntimeValue(b.String()), nil
+}
+
+func (e *Evaluator) evaluateIndexExpr(indexExpr ast.IndexExpr) (*runtime.RuntimeValue, *runtime.RuntimeError) {
+	val, err := e.EvaluateExpr(indexExpr.Node.ExtractExpr())
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := e.EvaluateExpr(indexExpr.Index.ExtractExpr())
+	if err != nil {
+		return nil, err
+	}
+
+	if val == nil || index == nil {
+		return nil, runtime.NewRuntimeError(runtime.ExpectedExprErrBuilder("expression"), indexExpr.ParseExpr(), indexExpr.Span)
+	}
+
+	return IndexOp(*val, *index, indexExpr.Span)
+}
+
 func (e *Evaluator) evaluteGroupingExpr(groupingExpr ast.GroupingExpr) (*runtime.RuntimeValue, *runtime.RuntimeError) {
 	return e.EvaluateExpr(groupingExpr.Node.ExtractExpr())
 }
 
+// the right operand is only evaluated if the left one doesn't already decide the result. in truthy
+// mode the operand which decided the result is returned as is, otherwise both operands must be bools
 func (e *Evaluator) evaluteLogicalExpr(logicalExpr ast.LogicalExpr) (*runtime.RuntimeValue, *runtime.RuntimeError) {
+	operator := logicalExpr.Operator
+
+	if operator != tokens.AND && operator != tokens.OR {
+		return nil, runtime.NewRuntimeError(runtime.INVALID_OPERATOR, operator.Literal(), logicalExpr.Span)
+	}
+
 	left, err := e.EvaluateExpr(logicalExpr.Left)
 	if err != nil {
 		return nil, err
 	}
 
-	right, err := e.EvaluateExpr(logicalExpr.Right)
-	if err != nil {
-		return nil, err
+	if left == nil {
+		left = runtime.NewRuntimeValue(nil)
 	}
 
-	operator := logicalExpr.Operator
+	var leftBool bool
 
-	switch operator {
-	case tokens.AND:
-		leftBool, isLeftBool := left.Value.(bool)
-		rightBool, isRightBool := right.Value.(bool)
+	if e.Runtime.TruthyLogic {
+		leftBool = runtime.IsTruthy(*left)
+	} else {
+		val, isBool := left.Value.(bool)
+		if !isBool {
+			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("bool"), operator.Literal(), logicalExpr.Span)
+		}
+		leftBool = val
+	}
 
-		if !(isLeftBool && isRightBool) {
-			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("bool"), logicalExpr.Operator.Literal(), logicalExpr.Line)
+	// `false && ...` and `true || ...` are decided by the left operand alone
+	if (operator == tokens.AND && !leftBool) || (operator == tokens.OR && leftBool) {
+		if e.Runtime.TruthyLogic {
+			return left, nil
 		}
 
-		return runtime.NewRuntimeValue(leftBool && rightBool), nil
-	case tokens.OR:
-		leftBool, isLeftBool := left.Value.(bool)
-		rightBool, isRightBool := right.Value.(bool)
+		return runtime.NewRuntimeValue(leftBool), nil
+	}
 
-		if !(isLeftBool && isRightBool) {
-			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("bool"), logicalExpr.Operator.Literal(), logicalExpr.Line)
-		}
+	right, err := e.EvaluateExpr(logicalExpr.Right)
+	if err != nil {
+		return nil, err
+	}
 
-		return runtime.NewRuntimeValue(leftBool || rightBool), nil
-	default:
-		return nil, runtime.NewRuntimeError(runtime.INVALID_OPERATOR, logicalExpr.Operator.Literal(), logicalExpr.Line)
+	if right == nil {
+		right = runtime.NewRuntimeValue(nil)
+	}
+
+	if e.Runtime.TruthyLogic {
+		return right, nil
+	}
+
+	if _, isBool := right.Value.(bool); !isBool {
+		return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("bool"), operator.Literal(), logicalExpr.Span)
 	}
+
+	return right, nil
 }
 
 func (e *Evaluator) evaluteUnaryExpr(unaryExpr ast.UnaryExpr) (*runtime.RuntimeValue, *runtime.RuntimeError) {
@@ -166,21 +407,11 @@ func (e *Evaluator) evaluteUnaryExpr(unaryExpr ast.UnaryExpr) (*runtime.RuntimeV
 		return nil, err
 	}
 
-	if unaryExpr.Operator == tokens.MINUS {
-		valNum, isNum := val.Value.(float64)
-		if !isNum {
-			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("number"), unaryExpr.Operator.Literal(), unaryExpr.Line)
-		}
-		val = runtime.NewRuntimeValue(-1 * valNum)
-	} else {
-		if val.Value != true && val.Value != false {
-			val = runtime.NewRuntimeValue(false)
-		} else {
-			val = runtime.NewRuntimeValue(!val.Value.(bool))
-		}
+	if val == nil {
+		val = runtime.NewRuntimeValue(nil)
 	}
 
-	return val, nil
+	return UnaryOp(unaryExpr.Operator, *val, e.Runtime.TruthyLogic, unaryExpr.Span)
 }
 
 func (e *Evaluator) evaluateBinaryExpr(binaryExpr ast.BinaryExpr) (*runtime.RuntimeValue, *runtime.RuntimeError) {
@@ -194,130 +425,113 @@ func (e *Evaluator) evaluateBinaryExpr(binaryExpr ast.BinaryExpr) (*runtime.Runt
 		return nil, err
 	}
 
-	operator := binaryExpr.Operator
-
-	switch operator {
-	case tokens.PLUS:
-		leftStr, isLeftStr := left.Value.(string)
-		rightStr, isRightStr := right.Value.(string)
-		leftNum, isLeftNum := left.Value.(float64)
-		rightNum, isRightNum := right.Value.(float64)
-
-		if isLeftStr {
-			if !isRightStr {
-				return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("string"), binaryExpr.Operator.Literal(), binaryExpr.Line)
-			}
+	if left == nil {
+		left = runtime.NewRuntimeValue(nil)
+	}
 
-			return runtime.NewRuntimeValue(leftStr + rightStr), nil
-		} else if isLeftNum {
-			if !isRightNum {
-				return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("number"), binaryExpr.Operator.Literal(), binaryExpr.Line)
-			}
+	if right == nil {
+		right = runtime.NewRuntimeValue(nil)
+	}
 
-			return runtime.NewRuntimeValue(leftNum + rightNum), nil
-		} else {
-			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("string", "number"), binaryExpr.Operator.Literal(), binaryExpr.Line)
-		}
-	case tokens.MINUS:
-		leftNum, isLeftNum := left.Value.(float64)
-		rightNum, isRightNum := right.Value.(float64)
+	return BinaryOp(binaryExpr.Operator, *left, *right, binaryExpr.Span)
+}
 
-		if !(isLeftNum && isRightNum) {
-			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("number"), binaryExpr.Operator.Literal(), binaryExpr.Line)
-		}
+func (e *Evaluator) evaluateIncrementExpr(incrementExpr ast.IncrementExpr) (*runtime.RuntimeValue, *runtime.RuntimeError) {
+	val := e.GetVar(incrementExpr.Name, *incrementExpr.Binding)
+	if val == nil {
+		return nil, runtime.NewRuntimeError(runtime.UNDEFINED_IDENTIFIER, incrementExpr.Name, incrementExpr.Span)
+	}
 
-		return runtime.NewRuntimeValue(leftNum - rightNum), nil
-	case tokens.STAR:
-		leftNum, isLeftNum := left.Value.(float64)
-		rightNum, isRightNum := right.Value.(float64)
+	// `val` points at the variable, which is about to be overwritten
+	prevVal := runtime.NewRuntimeValue(val.Value)
 
-		if !(isLeftNum && isRightNum) {
-			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("number"), binaryExpr.Operator.Literal(), binaryExpr.Line)
-		}
+	delta := int64(1)
+	if incrementExpr.Operator == tokens.MINUS_MINUS {
+		delta = -1
+	}
 
-		return runtime.NewRuntimeValue(leftNum * rightNum), nil
-	case tokens.SLASH:
-		leftNum, isLeftNum := left.Value.(float64)
-		rightNum, isRightNum := right.Value.(float64)
+	newVal, err := IncrementOp(*prevVal, delta, incrementExpr.Name, incrementExpr.Span)
+	if err != nil {
+		return nil, err
+	}
 
-		if !(isLeftNum && isRightNum) {
-			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("number"), binaryExpr.Operator.Literal(), binaryExpr.Line)
-		}
+	e.SetVar(incrementExpr.Name, *incrementExpr.Binding, *newVal)
 
-		if rightNum == 0 {
-			return nil, runtime.NewRuntimeError("ya buddy did you really graduate high school? you can't divide by 0, bruh", binaryExpr.Operator.Literal(), binaryExpr.Line)
-		}
+	if incrementExpr.IsPrefix {
+		return newVal, nil
+	}
 
-		return runtime.NewRuntimeValue(leftNum / rightNum), nil
-	case tokens.MODULO:
-		leftNum, isLeftNum := left.Value.(float64)
-		rightNum, isRightNum := right.Value.(float64)
+	return prevVal, nil
+}
 
-		if !(isLeftNum && isRightNum) {
-			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("number"), binaryExpr.Operator.Literal(), binaryExpr.Line)
-		}
+// the variable is read before the value is evaluated, same as in the vm
+func (e *Evaluator) evaluateCompoundAssignExpr(compoundAssignExpr ast.CompoundAssignExpr) (*runtime.RuntimeValue, *runtime.RuntimeError) {
+	val := e.GetVar(compoundAssignExpr.Name, *compoundAssignExpr.Binding)
+	if val == nil {
+		return nil, runtime.NewRuntimeError(runtime.UNDEFINED_IDENTIFIER, compoundAssignExpr.Name, compoundAssignExpr.Span)
+	}
 
-		isLeftInt := leftNum == math.Floor(leftNum)
-		isRightInt := rightNum == math.Floor(rightNum)
+	left := runtime.NewRuntimeValue(val.Value)
 
-		if !(isLeftInt && isRightInt) {
-			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("int"), binaryExpr.Operator.Literal(), binaryExpr.Line)
-		}
+	right, err := e.EvaluateExpr(compoundAssignExpr.Value)
+	if err != nil {
+		return nil, err
+	}
 
-		if rightNum == 0 {
-			return nil, runtime.NewRuntimeError("ya buddy did you really graduate high school? you can't divide by 0, bruh", binaryExpr.Operator.Literal(), binaryExpr.Line)
-		}
+	if right == nil {
+		right = runtime.NewRuntimeValue(nil)
+	}
 
-		return runtime.NewRuntimeValue(float64(int(leftNum) % int(rightNum))), nil
-	case tokens.LESS:
-		leftNum, isLeftNum := left.Value.(float64)
-		rightNum, isRightNum := right.Value.(float64)
+	newVal, err := BinaryOp(compoundAssignExpr.Operator, *left, *right, compoundAssignExpr.Span)
+	if err != nil {
+		return nil, err
+	}
 
-		if !(isLeftNum && isRightNum) {
-			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("number"), binaryExpr.Operator.Literal(), binaryExpr.Line)
-		}
+	e.SetVar(compoundAssignExpr.Name, *compoundAssignExpr.Binding, *newVal)
+	return newVal, nil
+}
 
-		return runtime.NewRuntimeValue(leftNum < rightNum), nil
-	case tokens.LESS_EQUAL:
-		leftNum, isLeftNum := left.Value.(float64)
-		rightNum, isRightNum := right.Value.(float64)
+// the value is evaluated before the variable is looked up, same as in the vm
+func (e *Evaluator) evaluateAssignExpr(assignExpr ast.AssignExpr) (*runtime.RuntimeValue, *runtime.RuntimeError) {
+	val, err := e.EvaluateExpr(assignExpr.Value)
+	if err != nil {
+		return nil, err
+	}
 
-		if !(isLeftNum && isRightNum) {
-			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("number"), binaryExpr.Operator.Literal(), binaryExpr.Line)
-		}
+	if val == nil {
+		val = runtime.NewRuntimeValue(nil)
+	}
 
-		return runtime.NewRuntimeValue(leftNum <= rightNum), nil
-	case tokens.GREATER:
-		leftNum, isLeftNum := left.Value.(float64)
-		rightNum, isRightNum := right.Value.(float64)
+	if !e.SetVar(assignExpr.Name, *assignExpr.Binding, *val) {
+		return nil, runtime.NewRuntimeError(runtime.UNDEFINED_IDENTIFIER, assignExpr.Name, assignExpr.Span)
+	}
 
-		if !(isLeftNum && isRightNum) {
-			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("number"), binaryExpr.Operator.Literal(), binaryExpr.Line)
-		}
+	return val, nil
+}
 
-		return runtime.NewRuntimeValue(leftNum > rightNum), nil
-	case tokens.GREATER_EQUAL:
-		leftNum, isLeftNum := left.Value.(float64)
-		rightNum, isRightNum := right.Value.(float64)
+func (e *Evaluator) evaluateIndexAssignExpr(indexAssignExpr ast.IndexAssignExpr) (*runtime.RuntimeValue, *runtime.RuntimeError) {
+	target, err := e.EvaluateExpr(indexAssignExpr.Target.Node.ExtractExpr())
+	if err != nil {
+		return nil, err
+	}
 
-		if !(isLeftNum && isRightNum) {
-			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("number"), binaryExpr.Operator.Literal(), binaryExpr.Line)
-		}
+	index, err := e.EvaluateExpr(indexAssignExpr.Target.Index.ExtractExpr())
+	if err != nil {
+		return nil, err
+	}
 
-		return runtime.NewRuntimeValue(leftNum >= rightNum), nil
-	case tokens.EQUAL_EQUAL:
-		if reflect.TypeOf(left.Value) != reflect.TypeOf(right.Value) {
-			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("same"), binaryExpr.Operator.Literal(), binaryExpr.Line)
-		}
+	val, err := e.EvaluateExpr(indexAssignExpr.Value)
+	if err != nil {
+		return nil, err
+	}
 
-		return runtime.NewRuntimeValue(left.Value == right.Value), nil
-	case tokens.BANG_EQUAL:
-		if reflect.TypeOf(left.Value) != reflect.TypeOf(right.Value) {
-			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("same"), binaryExpr.Operator.Literal(), binaryExpr.Line)
-		}
+	if target == nil || index == nil || val == nil {
+		return nil, runtime.NewRuntimeError(runtime.ExpectedExprErrBuilder("expression"), indexAssignExpr.Target.ParseExpr(), indexAssignExpr.Span)
+	}
 
-		return runtime.NewRuntimeValue(left.Value != right.Value), nil
-	default:
-		return nil, runtime.NewRuntimeError(runtime.INVALID_OPERATOR, binaryExpr.Operator.Literal(), binaryExpr.Line)
+	if err := SetIndexOp(*target, *index, *val, indexAssignExpr.Span); err != nil {
+		return nil, err
 	}
+
+	return val, nil
 }
@@ -0,0 +1,167 @@
+package evaluator_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/0xmukesh/interpreter/brtlang"
+	"github.com/0xmukesh/interpreter/internal/diagnostics"
+	"github.com/0xmukesh/interpreter/internal/runtime"
+)
+
+// a program along with what it prints
+type outputTest struct {
+	src      string
+	expected string
+}
+
+// a program which stops with a runtime error
+type errorTest struct {
+	src     string
+	message diagnostics.Message
+	at      string
+}
+
+func eval(src string) (string, error) {
+	var out bytes.Buffer
+
+	_, err := brtlang.New(brtlang.WithStdout(&out), brtlang.WithStderr(io.Discard)).Eval([]byte(src))
+	return out.String(), err
+}
+
+func runOutputTests(t *testing.T, tests []outputTest) {
+	t.Helper()
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			out, err := eval(tt.src)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			if out != tt.expected+"\n" {
+				t.Errorf("expected %q, got %q", tt.expected+"\n", out)
+			}
+		})
+	}
+}
+
+func runErrorTests(t *testing.T, tests []errorTest) {
+	t.Helper()
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := eval(tt.src)
+
+			runtimeErr, ok := err.(*runtime.RuntimeError)
+			if !ok {
+				t.Fatalf("expected a runtime error, got %v", err)
+			}
+
+			if runtimeErr.Code != tt.message.Code || runtimeErr.At != tt.at {
+				t.Errorf("got %s at %q, expected %s at %q", runtimeErr.Code, runtimeErr.At, tt.message.Code, tt.at)
+			}
+		})
+	}
+}
+
+func TestIntAndFloatArithmetic(t *testing.T) {
+	runOutputTests(t, []outputTest{
+		{"yap(7 / 2);", "3"},
+		{"yap(-7 / 2);", "-3"},
+		{"yap(7 / 2.0);", "3.5"},
+		{"yap(-7 % 4);", "-3"},
+		{"yap(7.5 % 2);", "1.5"},
+		{"yap(2.5 * 2);", "5.0"},
+		{"yap(0.1 + 0.2);", "0.30000000000000004"},
+		{"yap(1 / 3.0);", "0.3333333333333333"},
+		{"yap(6.02e23 / 1e-7);", "6.02e+30"},
+		{"yap(9007199254740993);", "9007199254740993"},
+		{"yap(9223372036854775807 + 1);", "-9223372036854775808"},
+		{"yap(0xff + 0b1010 + 0o17 + 1_000);", "1280"},
+		{"yap(1 == 1.0);", "true"},
+		{"yap(3 < 3.5);", "true"},
+		{"yap([1, 2.0] == [1.0, 2]);", "true"},
+		{`rizz m = {1: "int"}; m[1.0] = "float"; yap(m);`, `{1: "float"}`},
+		{"rizz f = 1.5; f++; yap(f);", "2.5"},
+	})
+}
+
+func TestDivisionByZero(t *testing.T) {
+	runErrorTests(t, []errorTest{
+		{"yap(7 / 0);", runtime.DIVIDE_BY_ZERO, "/"},
+		{"yap(7 % 0);", runtime.DIVIDE_BY_ZERO, "%"},
+		{"yap(1 / 0.0);", runtime.DIVIDE_BY_ZERO, "/"},
+	})
+}
+
+func TestBigNumbers(t *testing.T) {
+	runOutputTests(t, []outputTest{
+		{"yap(1.10d * 3d);", "3.30"},
+		{"yap(0.1d + 0.2d);", "0.3"},
+		{"yap(1d / 3d);", "0.3333333333333333"},
+		{"yap(-7.5d % 2d);", "-1.5"},
+		{"yap(1.10d == 1.1d);", "true"},
+		{"rizz big = 9223372036854775807n; yap(big * big);", "85070591730234615847396907784232501249"},
+		{"yap(-7n / 2n);", "-3"},
+		{"rizz n = 1n; n++; yap(n);", "2"},
+		{`yap(bigint(2.9d) + bigint("40"));`, "42"},
+		{`yap(decimal(0.1) + decimal("0.2"));`, "0.3"},
+		{`yap({1.5d: "a", 1.50d: "b"});`, `{1.5: "b"}`},
+	})
+}
+
+func TestMixingNumbers(t *testing.T) {
+	runErrorTests(t, []errorTest{
+		{"yap(1n + 1);", runtime.MIXED_NUMBERS_TEMPLATE, "+"},
+		{"yap(1.5d + 1.5);", runtime.MIXED_NUMBERS_TEMPLATE, "+"},
+		{"yap(2n < 3);", runtime.MIXED_NUMBERS_TEMPLATE, "<"},
+		{"yap(1n / 0n);", runtime.DIVIDE_BY_ZERO, "/"},
+		{"yap(1d / 0d);", runtime.DIVIDE_BY_ZERO, "/"},
+		{`yap(bigint("x"));`, runtime.INVALID_BIG_INT, "bigint"},
+	})
+}
+
+func TestBitwiseOps(t *testing.T) {
+	runOutputTests(t, []outputTest{
+		{"yap([6 & 3, 6 | 3, 6 ^ 3, ~5]);", "[2, 7, 5, -6]"},
+		{"yap([1 << 4 + 1, -16 >> 2, 1 << 64]);", "[32, -4, 0]"},
+		{"yap([2 ** 10, -2 ** 2, 2 ** 3 ** 2]);", "[1024, -4, 512]"},
+		{"yap([bigint(2) ** 100n, ~0n & 7n, 1n << 70n]);", "[1267650600228229401496703205376, 7, 1180591620717411303424]"},
+		{"yap(1 | 2 == 3);", "true"},
+	})
+}
+
+func TestBitwiseOpErrors(t *testing.T) {
+	runErrorTests(t, []errorTest{
+		{"yap(1.5 & 1);", runtime.OPERANDS_MUST_BE_OF_TEMPLATE, "&"},
+		{"yap(~1.5);", runtime.OPERANDS_MUST_BE_OF_TEMPLATE, "~"},
+		{"yap(2.0 ** 0.5);", runtime.OPERANDS_MUST_BE_OF_TEMPLATE, "**"},
+		{"yap(2 ** -1);", runtime.NEGATIVE_EXPONENT, "**"},
+		{"yap(1 << -1);", runtime.NEGATIVE_SHIFT, "<<"},
+		{"yap(1n << -1n);", runtime.NEGATIVE_SHIFT, "<<"},
+		{"yap(1n << 18446744073709551616n);", runtime.BIG_INT_TOO_LARGE, "<<"},
+	})
+}
+
+func TestAssignmentExprs(t *testing.T) {
+	runOutputTests(t, []outputTest{
+		{"rizz i = 5; yap([i++, i, ++i, i--, --i]);", "[5, 6, 7, 7, 5]"},
+		{"rizz x = 2; rizz y = 3; x *= y += 1; yap([x, y]);", "[8, 4]"},
+		{`rizz s = "a"; s += "b"; yap(s);`, "ab"},
+		{"rizz x = 1; rizz y = 2; x = y = 3; yap([x, y]);", "[3, 3]"},
+		{"skibidi f(a) { bussin a; } rizz x = 0; yap(f(x = 1)); yap(x);", "1\n1"},
+		{"rizz xs = [1, 2]; yap(xs[0] = 7); yap(xs);", "7\n[7, 2]"},
+		{`rizz m = {}; m["a"] = m["b"] = 1; yap(m);`, `{"b": 1, "a": 1}`},
+		{"rizz t = 0; chillin (rizz j = 0; j < 10; j += 4) { t += j; } yap(t);", "12"},
+	})
+}
+
+func TestAssignmentExprErrors(t *testing.T) {
+	runErrorTests(t, []errorTest{
+		{`rizz s = "a"; s -= 1;`, runtime.OPERANDS_MUST_BE_OF_TEMPLATE, "-"},
+		{`rizz s = "a"; yap(s++);`, runtime.OPERANDS_MUST_BE_OF_TEMPLATE, "s"},
+		{"rizz x = 1; x /= 0;", runtime.DIVIDE_BY_ZERO, "/"},
+	})
+}
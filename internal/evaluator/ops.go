@@ -0,0 +1,330 @@
+package evaluator
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/0xmukesh/interpreter/internal/runtime"
+	"github.com/0xmukesh/interpreter/internal/tokens"
+)
+
+// operations shared by the tree-walking evaluator and the bytecode vm, so both report the same
+// results and errors
+
+// the operands of an arithmetic or comparison operator. if either one is a float both are used as
+// floats, otherwise both are ints
+type numOperands struct {
+	isFloat               bool
+	leftInt, rightInt     int64
+	leftFloat, rightFloat float64
+}
+
+// reports false if either operand isn't a number
+func toNumOperands(left runtime.RuntimeValue, right runtime.RuntimeValue) (numOperands, bool) {
+	leftInt, isLeftInt := left.Value.(int64)
+	rightInt, isRightInt := right.Value.(int64)
+
+	if isLeftInt && isRightInt {
+		return numOperands{leftInt: leftInt, rightInt: rightInt}, true
+	}
+
+	leftFloat, isLeftNum := runtime.ToFloat(left)
+	rightFloat, isRightNum := runtime.ToFloat(right)
+
+	if !(isLeftNum && isRightNum) {
+		return numOperands{}, false
+	}
+
+	return numOperands{isFloat: true, leftFloat: leftFloat, rightFloat: rightFloat}, true
+}
+
+// applies `+`, `-`, `*`, `/` or `%` to numbers. ints stay ints, so `/` on ints drops the fraction
+func arithmeticOp(operator tokens.TokenType, nums numOperands, span tokens.Span) (*runtime.RuntimeValue, *runtime.RuntimeError) {
+	isDivision := operator == tokens.SLASH || operator == tokens.MODULO
+
+	if nums.isFloat {
+		left, right := nums.leftFloat, nums.rightFloat
+
+		if isDivision && right == 0 {
+			return nil, runtime.NewRuntimeError(runtime.DIVIDE_BY_ZERO, operator.Literal(), span)
+		}
+
+		switch operator {
+		case tokens.PLUS:
+			return runtime.NewRuntimeValue(left + right), nil
+		case tokens.MINUS:
+			return runtime.NewRuntimeValue(left - right), nil
+		case tokens.STAR:
+			return runtime.NewRuntimeValue(left * right), nil
+		case tokens.SLASH:
+			return runtime.NewRuntimeValue(left / right), nil
+		default:
+			return runtime.NewRuntimeValue(math.Mod(left, right)), nil
+		}
+	}
+
+	left, right := nums.leftInt, nums.rightInt
+
+	if isDivision && right == 0 {
+		return nil, runtime.NewRuntimeError(runtime.DIVIDE_BY_ZERO, operator.Literal(), span)
+	}
+
+	switch operator {
+	case tokens.PLUS:
+		return runtime.NewRuntimeValue(left + right), nil
+	case tokens.MINUS:
+		return runtime.NewRuntimeValue(left - right), nil
+	case tokens.STAR:
+		return runtime.NewRuntimeValue(left * right), nil
+	case tokens.SLASH:
+		return runtime.NewRuntimeValue(left / right), nil
+	default:
+		return runtime.NewRuntimeValue(left % right), nil
+	}
+}
+
+// applies `&`, `|`, `^`, `<<`, `>>` or `**` to ints. like the other operators, `<<` and `**` wrap
+// around on overflow
+func integerOp(operator tokens.TokenType, left int64, right int64, span tokens.Span) (*runtime.RuntimeValue, *runtime.RuntimeError) {
+	switch operator {
+	case tokens.BIT_AND:
+		return runtime.NewRuntimeValue(left & right), nil
+	case tokens.BIT_OR:
+		return runtime.NewRuntimeValue(left | right), nil
+	case tokens.BIT_XOR:
+		return runtime.NewRuntimeValue(left ^ right), nil
+	case tokens.LESS_LESS, tokens.GREATER_GREATER:
+		if right < 0 {
+			return nil, runtime.NewRuntimeError(runtime.NEGATIVE_SHIFT, operator.Literal(), span)
+		}
+
+		if operator == tokens.LESS_LESS {
+			return runtime.NewRuntimeValue(left << right), nil
+		}
+
+		return runtime.NewRuntimeValue(left >> right), nil
+	default:
+		if right < 0 {
+			return nil, runtime.NewRuntimeError(runtime.NEGATIVE_EXPONENT, operator.Literal(), span)
+		}
+
+		return runtime.NewRuntimeValue(power(left, right)), nil
+	}
+}
+
+// `base` to the power of `exp`, which isn't negative, by squaring
+func power(base int64, exp int64) int64 {
+	result := int64(1)
+
+	for exp > 0 {
+		if exp&1 == 1 {
+			result *= base
+		}
+
+		base *= base
+		exp >>= 1
+	}
+
+	return result
+}
+
+// applies `<`, `<=`, `>` or `>=` to numbers
+func compareOp(operator tokens.TokenType, nums numOperands) *runtime.RuntimeValue {
+	if nums.isFloat {
+		return runtime.NewRuntimeValue(compare(operator, nums.leftFloat, nums.rightFloat))
+	}
+
+	return runtime.NewRuntimeValue(compare(operator, nums.leftInt, nums.rightInt))
+}
+
+func compare[T int64 | float64](operator tokens.TokenType, left T, right T) bool {
+	switch operator {
+	case tokens.LESS:
+		return left < right
+	case tokens.LESS_EQUAL:
+		return left <= right
+	case tokens.GREATER:
+		return left > right
+	default:
+		return left >= right
+	}
+}
+
+// applies a binary (non-logical) operator to both operands
+func BinaryOp(operator tokens.TokenType, left runtime.RuntimeValue, right runtime.RuntimeValue, span tokens.Span) (*runtime.RuntimeValue, *runtime.RuntimeError) {
+	// a left operand which isn't a number is reported by the checks below
+	if isBigNumber(left) || (isBigNumber(right) && runtime.IsNumber(left)) {
+		return bigNumberOp(operator, left, right, span)
+	}
+
+	switch operator {
+	case tokens.PLUS:
+		if leftStr, isLeftStr := left.Value.(string); isLeftStr {
+			rightStr, isRightStr := right.Value.(string)
+			if !isRightStr {
+				return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("string"), operator.Literal(), span)
+			}
+
+			return runtime.NewRuntimeValue(leftStr + rightStr), nil
+		}
+
+		if _, isLeftNum := runtime.ToFloat(left); !isLeftNum {
+			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("string", "number"), operator.Literal(), span)
+		}
+
+		nums, ok := toNumOperands(left, right)
+		if !ok {
+			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("number"), operator.Literal(), span)
+		}
+
+		return arithmeticOp(operator, nums, span)
+	case tokens.MINUS, tokens.STAR, tokens.SLASH, tokens.MODULO:
+		nums, ok := toNumOperands(left, right)
+		if !ok {
+			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("number"), operator.Literal(), span)
+		}
+
+		return arithmeticOp(operator, nums, span)
+	case tokens.LESS, tokens.LESS_EQUAL, tokens.GREATER, tokens.GREATER_EQUAL:
+		nums, ok := toNumOperands(left, right)
+		if !ok {
+			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("number"), operator.Literal(), span)
+		}
+
+		return compareOp(operator, nums), nil
+	case tokens.BIT_AND, tokens.BIT_OR, tokens.BIT_XOR, tokens.LESS_LESS, tokens.GREATER_GREATER, tokens.STAR_STAR:
+		leftInt, isLeftInt := left.Value.(int64)
+		rightInt, isRightInt := right.Value.(int64)
+
+		if !(isLeftInt && isRightInt) {
+			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("int"), operator.Literal(), span)
+		}
+
+		return integerOp(operator, leftInt, rightInt, span)
+	case tokens.EQUAL_EQUAL:
+		if !runtime.SameType(left, right) {
+			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("same"), operator.Literal(), span)
+		}
+
+		return runtime.NewRuntimeValue(left.Equals(right)), nil
+	case tokens.BANG_EQUAL:
+		if !runtime.SameType(left, right) {
+			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("same"), operator.Literal(), span)
+		}
+
+		return runtime.NewRuntimeValue(!left.Equals(right)), nil
+	default:
+		return nil, runtime.NewRuntimeError(runtime.INVALID_OPERATOR, operator.Literal(), span)
+	}
+}
+
+// applies a prefix operator. `~` flips the bits of an int. `!` negates the operand's truthiness in
+// truthy mode, otherwise anything but a bool negates to false
+func UnaryOp(operator tokens.TokenType, val runtime.RuntimeValue, truthy bool, span tokens.Span) (*runtime.RuntimeValue, *runtime.RuntimeError) {
+	if operator == tokens.BIT_NOT {
+		switch v := val.Value.(type) {
+		case int64:
+			return runtime.NewRuntimeValue(^v), nil
+		case *big.Int:
+			return runtime.NewRuntimeValue(new(big.Int).Not(v)), nil
+		default:
+			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("int"), operator.Literal(), span)
+		}
+	}
+
+	if operator == tokens.MINUS {
+		switch v := val.Value.(type) {
+		case int64:
+			return runtime.NewRuntimeValue(-v), nil
+		case float64:
+			return runtime.NewRuntimeValue(-v), nil
+		case *big.Int:
+			return runtime.NewRuntimeValue(new(big.Int).Neg(v)), nil
+		case *runtime.Decimal:
+			return runtime.NewRuntimeValue(v.Neg()), nil
+		default:
+			return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("number"), operator.Literal(), span)
+		}
+	}
+
+	if truthy {
+		return runtime.NewRuntimeValue(!runtime.IsTruthy(val)), nil
+	}
+
+	if b, isBool := val.Value.(bool); isBool {
+		return runtime.NewRuntimeValue(!b), nil
+	}
+
+	return runtime.NewRuntimeValue(false), nil
+}
+
+// adds `delta` to the number in the variable `name`, used by `++` and `--`
+func IncrementOp(val runtime.RuntimeValue, delta int64, name string, span tokens.Span) (*runtime.RuntimeValue, *runtime.RuntimeError) {
+	switch v := val.Value.(type) {
+	case int64:
+		return runtime.NewRuntimeValue(v + delta), nil
+	case float64:
+		return runtime.NewRuntimeValue(v + float64(delta)), nil
+	case *big.Int:
+		return runtime.NewRuntimeValue(new(big.Int).Add(v, big.NewInt(delta))), nil
+	case *runtime.Decimal:
+		return runtime.NewRuntimeValue(v.Add(runtime.NewDecimal(big.NewInt(delta), 0))), nil
+	default:
+		return nil, runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("number"), name, span)
+	}
+}
+
+// reads `val[index]` from a list, map or string
+func IndexOp(val runtime.RuntimeValue, index runtime.RuntimeValue, span tokens.Span) (*runtime.RuntimeValue, *runtime.RuntimeError) {
+	switch v := val.Value.(type) {
+	case *runtime.List:
+		i, indexErr := runtime.ToIndex(index, len(v.Elements))
+		if indexErr != nil {
+			return nil, runtime.NewRuntimeError(runtime.MessageOf(indexErr), index.String(), span)
+		}
+
+		return runtime.NewRuntimeValue(v.Elements[i].Value), nil
+	case *runtime.Map:
+		if keyErr := runtime.ValidateMapKey(index); keyErr != nil {
+			return nil, runtime.NewRuntimeError(runtime.MessageOf(keyErr), index.String(), span)
+		}
+
+		// missing keys read as nada, `has` tells them apart from keys explicitly set to nada
+		entry, _ := v.Get(index)
+		return runtime.NewRuntimeValue(entry.Value), nil
+	case string:
+		chars := []rune(v)
+
+		i, indexErr := runtime.ToIndex(index, len(chars))
+		if indexErr != nil {
+			return nil, runtime.NewRuntimeError(runtime.MessageOf(indexErr), index.String(), span)
+		}
+
+		return runtime.NewRuntimeValue(string(chars[i])), nil
+	default:
+		return nil, runtime.NewRuntimeError(runtime.NOT_INDEXABLE, val.String(), span)
+	}
+}
+
+// writes `val` to `target[index]` in a list or map
+func SetIndexOp(target runtime.RuntimeValue, index runtime.RuntimeValue, val runtime.RuntimeValue, span tokens.Span) *runtime.RuntimeError {
+	switch collection := target.Value.(type) {
+	case *runtime.List:
+		i, indexErr := runtime.ToIndex(index, len(collection.Elements))
+		if indexErr != nil {
+			return runtime.NewRuntimeError(runtime.MessageOf(indexErr), index.String(), span)
+		}
+
+		collection.Elements[i] = val
+	case *runtime.Map:
+		if keyErr := runtime.ValidateMapKey(index); keyErr != nil {
+			return runtime.NewRuntimeError(runtime.MessageOf(keyErr), index.String(), span)
+		}
+
+		collection.Set(index, val)
+	default:
+		return runtime.NewRuntimeError(runtime.NOT_INDEXABLE, target.String(), span)
+	}
+
+	return nil
+}
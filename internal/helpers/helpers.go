@@ -1,23 +1,14 @@
 package helpers
 
 import (
-	"fmt"
-	"os"
-
 	"github.com/0xmukesh/interpreter/internal/lexer"
 	"github.com/0xmukesh/interpreter/internal/tokens"
-	"github.com/0xmukesh/interpreter/internal/utils"
 )
 
-func ProcessTokens(l *lexer.Lexer, exitOnError bool) []tokens.Token {
+func ProcessTokens(l *lexer.Lexer) ([]tokens.Token, *lexer.LexerError) {
 	tkns, err := l.LexAll()
 	if err != nil {
-		errMsg := fmt.Sprintf("[line %d] Error: %s\n", l.Line, err.Error())
-		if exitOnError {
-			utils.EPrint(errMsg)
-		}
-		fmt.Fprint(os.Stderr, errMsg)
-		return nil
+		return nil, err
 	}
 
 	var filteredTkns []tokens.Token
@@ -26,16 +17,11 @@ func ProcessTokens(l *lexer.Lexer, exitOnError bool) []tokens.Token {
 		case tokens.IGNORE:
 			continue
 		case tokens.ILLEGAL:
-			errMsg := fmt.Sprintf("[line %d] Error: Unexpected character: %s\n", l.Line, tkn.Literal)
-			if exitOnError {
-				utils.EPrint(fmt.Sprintf("[line %d] Error: Unexpected character: %s\n", l.Line, tkn.Literal))
-			}
-			fmt.Fprint(os.Stderr, errMsg)
-			continue
+			return nil, lexer.NewLexerError(lexer.UNEXPECTED_CHARACTER_TEMPLATE.With(tkn.Literal), tkn.Span)
 		default:
 			filteredTkns = append(filteredTkns, tkn)
 		}
 	}
 
-	return filteredTkns
+	return filteredTkns, nil
 }
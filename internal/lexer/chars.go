@@ -9,10 +9,10 @@ func (l *Lexer) LexDoubleCharBuilder(expectedNextChar byte, doubleCharTknType, s
 
 	if nextChar == expectedNextChar {
 		l.read()
-		return tokens.NewToken(doubleCharTknType, doubleCharTknType.Literal(), "null", l.Line), nil
+		return tokens.NewToken(doubleCharTknType, doubleCharTknType.Literal(), "null", l.span()), nil
 	}
 
-	return tokens.NewToken(singleCharTknType, singleCharTknType.Literal(), "null", l.Line), nil
+	return tokens.NewToken(singleCharTknType, singleCharTknType.Literal(), "null", l.span()), nil
 }
 
 // scans "=" and "==" tokens
@@ -25,30 +25,64 @@ func (l *Lexer) LexBangChar() (*tokens.Token, *LexerError) {
 	return l.LexDoubleCharBuilder('=', tokens.BANG_EQUAL, tokens.BANG)
 }
 
-// scans "<" and "<=" tokens
+// scans "<", "<=" and "<<" tokens
 func (l *Lexer) LexLessChar() (*tokens.Token, *LexerError) {
+	if l.peek() == '<' {
+		return l.LexDoubleCharBuilder('<', tokens.LESS_LESS, tokens.LESS)
+	}
+
 	return l.LexDoubleCharBuilder('=', tokens.LESS_EQUAL, tokens.LESS)
 }
 
-// scans ">" and ">=" tokens
+// scans ">", ">=" and ">>" tokens
 func (l *Lexer) LexGreaterChar() (*tokens.Token, *LexerError) {
+	if l.peek() == '>' {
+		return l.LexDoubleCharBuilder('>', tokens.GREATER_GREATER, tokens.GREATER)
+	}
+
 	return l.LexDoubleCharBuilder('=', tokens.GREATER_EQUAL, tokens.GREATER)
 }
 
-// scans "+" and "++" tokens
+// scans "*", "**" and "*=" tokens
+func (l *Lexer) LexStarChar() (*tokens.Token, *LexerError) {
+	if l.peek() == '=' {
+		return l.LexDoubleCharBuilder('=', tokens.STAR_EQUAL, tokens.STAR)
+	}
+
+	return l.LexDoubleCharBuilder('*', tokens.STAR_STAR, tokens.STAR)
+}
+
+// scans "+", "++" and "+=" tokens
 func (l *Lexer) LexPlusChar() (*tokens.Token, *LexerError) {
+	if l.peek() == '=' {
+		return l.LexDoubleCharBuilder('=', tokens.PLUS_EQUAL, tokens.PLUS)
+	}
+
 	return l.LexDoubleCharBuilder('+', tokens.PLUS_PLUS, tokens.PLUS)
 }
 
-// scans "-" and "--" tokens
+// scans "-", "--" and "-=" tokens
 func (l *Lexer) LexMinusChar() (*tokens.Token, *LexerError) {
+	if l.peek() == '=' {
+		return l.LexDoubleCharBuilder('=', tokens.MINUS_EQUAL, tokens.MINUS)
+	}
+
 	return l.LexDoubleCharBuilder('-', tokens.MINUS_MINUS, tokens.MINUS)
 }
 
-// scans "/" token and "//" (comment)
+// scans "%" and "%=" tokens
+func (l *Lexer) LexModuloChar() (*tokens.Token, *LexerError) {
+	return l.LexDoubleCharBuilder('=', tokens.MODULO_EQUAL, tokens.MODULO)
+}
+
+// scans "/" and "/=" tokens and "//" (comment)
 func (l *Lexer) LexSlashChar() (*tokens.Token, *LexerError) {
 	nextChar := l.peek()
 
+	if nextChar == '=' {
+		return l.LexDoubleCharBuilder('=', tokens.SLASH_EQUAL, tokens.SLASH)
+	}
+
 	if nextChar == '/' {
 		for {
 			l.read()
@@ -58,32 +92,18 @@ func (l *Lexer) LexSlashChar() (*tokens.Token, *LexerError) {
 			}
 		}
 
-		return tokens.NewToken(tokens.IGNORE, "", "null", l.Line), nil
+		return tokens.NewToken(tokens.IGNORE, "", "null", l.span()), nil
 	}
 
-	return tokens.NewToken(tokens.SLASH, tokens.SLASH.Literal(), "null", l.Line), nil
+	return tokens.NewToken(tokens.SLASH, tokens.SLASH.Literal(), "null", l.span()), nil
 }
 
-// scans "&&" token
+// scans "&" and "&&" tokens
 func (l *Lexer) LexAmpersandChar() (*tokens.Token, *LexerError) {
-	nextChar := l.peek()
-
-	if nextChar == '&' {
-		l.read()
-		return tokens.NewToken(tokens.AND, tokens.AND.Literal(), "null", l.Line), nil
-	}
-
-	return nil, NewLexerError(`missing "&" character`, l.Line)
+	return l.LexDoubleCharBuilder('&', tokens.AND, tokens.BIT_AND)
 }
 
-// scans "||" token
+// scans "|" and "||" tokens
 func (l *Lexer) LexPipeChar() (*tokens.Token, *LexerError) {
-	nextChar := l.peek()
-
-	if nextChar == '|' {
-		l.read()
-		return tokens.NewToken(tokens.OR, tokens.OR.Literal(), "null", l.Line), nil
-	}
-
-	return nil, NewLexerError(`missing "|" character`, l.Line)
+	return l.LexDoubleCharBuilder('|', tokens.OR, tokens.BIT_OR)
 }
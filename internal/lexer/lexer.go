@@ -9,15 +9,38 @@ import (
 )
 
 type Lexer struct {
-	Src  []byte
-	Line int
-	Idx  int
-	Char byte
+	Src []byte
+	// line and column of `Char`, tracked as the source is read
+	Line   int
+	Column int
+	Idx    int
+	Char   byte
+	// position of the first character of the token being lexed
+	start tokens.Position
+	// the interpolations of strings which are being lexed, the innermost one is the last
+	interpolations []interpolation
+}
+
+// an interpolation is the "${...}" within a string
+type interpolation struct {
+	// position of the "$"
+	start tokens.Position
+	// number of "{" within the interpolation which aren't closed yet
+	depth int
+}
+
+// span of the "${" opening the interpolation
+func (i interpolation) span() tokens.Span {
+	return tokens.Span{
+		Start: i.start,
+		End:   tokens.Position{Offset: i.start.Offset + 2, Line: i.start.Line, Column: i.start.Column + 2},
+	}
 }
 
 func NewLexer(src []byte) *Lexer {
 	return &Lexer{
 		Src:  src,
+		Line: 1,
 		Idx:  0,
 		Char: 0,
 	}
@@ -33,11 +56,35 @@ func (l *Lexer) read() {
 		return
 	}
 
+	if l.Char == '\n' {
+		l.Line++
+		l.Column = 1
+	} else {
+		l.Column++
+	}
+
 	l.Char = l.Src[l.Idx]
-	l.Line = utils.FindLineNumber(l.Idx, l.Src)
 	l.Idx++
 }
 
+// position of `Char` within the source
+func (l *Lexer) position() tokens.Position {
+	return tokens.Position{Offset: l.Idx - 1, Line: l.Line, Column: l.Column}
+}
+
+// span from the start of the current token up to and including `Char`
+func (l *Lexer) span() tokens.Span {
+	return l.spanFrom(l.start)
+}
+
+// span from `start` up to and including `Char`
+func (l *Lexer) spanFrom(start tokens.Position) tokens.Span {
+	return tokens.Span{
+		Start: start,
+		End:   tokens.Position{Offset: l.Idx, Line: l.Line, Column: l.Column + 1},
+	}
+}
+
 func (l *Lexer) peek() byte {
 	if l.isAtEnd() {
 		return 0
@@ -51,6 +98,8 @@ func (l *Lexer) LexAll() ([]tokens.Token, *LexerError) {
 
 	for !l.isAtEnd() {
 		l.read()
+		l.start = l.position()
+
 		tkn, err := l.Lex()
 		if err != nil {
 			return nil, err
@@ -61,16 +110,36 @@ func (l *Lexer) LexAll() ([]tokens.Token, *LexerError) {
 		}
 	}
 
+	if len(l.interpolations) > 0 {
+		return nil, NewLexerError(UNTERMINATED_INTERPOLATION, l.interpolations[len(l.interpolations)-1].span())
+	}
+
 	return tkns, nil
 }
 
 func (l *Lexer) Lex() (*tokens.Token, *LexerError) {
 	if l.Char == 0 {
-		return tokens.NewToken(tokens.EOF, "", "null", l.Line), nil
+		return tokens.NewToken(tokens.EOF, "", "null", l.span()), nil
 	}
 
 	if utils.IsWhitespace(l.Char) {
-		return tokens.NewToken(tokens.IGNORE, "", "null", l.Line), nil
+		return tokens.NewToken(tokens.IGNORE, "", "null", l.span()), nil
+	}
+
+	if len(l.interpolations) > 0 {
+		curr := &l.interpolations[len(l.interpolations)-1]
+
+		switch l.Char {
+		case '{':
+			curr.depth++
+		case '}':
+			if curr.depth == 0 {
+				l.interpolations = l.interpolations[:len(l.interpolations)-1]
+				return l.lexStrSegment(true)
+			}
+
+			curr.depth--
+		}
 	}
 
 	if utf8.Valid([]byte{l.Char}) {
@@ -95,21 +164,26 @@ func (l *Lexer) Lex() (*tokens.Token, *LexerError) {
 				tkn, err = l.LexPlusChar()
 			case tokens.MINUS:
 				tkn, err = l.LexMinusChar()
+			case tokens.STAR:
+				tkn, err = l.LexStarChar()
+			case tokens.MODULO:
+				tkn, err = l.LexModuloChar()
+			case tokens.BIT_AND:
+				tkn, err = l.LexAmpersandChar()
+			case tokens.BIT_OR:
+				tkn, err = l.LexPipeChar()
 			default:
-				tkn = tokens.NewToken(*tknType, string(l.Char), "null", l.Line)
+				tkn = tokens.NewToken(*tknType, string(l.Char), "null", l.span())
 			}
 
 			return tkn, err
 		} else {
-			if l.Char == '&' {
-				tkn, err := l.LexAmpersandChar()
-				return tkn, err
-			} else if l.Char == '|' {
-				tkn, err := l.LexPipeChar()
-				return tkn, err
-			} else if l.Char == '"' {
+			if l.Char == '"' {
 				tkn, err := l.LexStrLiterals()
 				return tkn, err
+			} else if l.Char == '`' {
+				tkn, err := l.LexRawStrLiterals()
+				return tkn, err
 			} else if unicode.IsDigit(rune(l.Char)) {
 				tkn, err := l.LexNumLiterals()
 				return tkn, err
@@ -120,5 +194,5 @@ func (l *Lexer) Lex() (*tokens.Token, *LexerError) {
 		}
 	}
 
-	return tokens.NewToken(tokens.ILLEGAL, "", string(l.Char), l.Line), nil
+	return tokens.NewToken(tokens.ILLEGAL, "", string(l.Char), l.span()), nil
 }
@@ -2,20 +2,75 @@ package lexer
 
 import (
 	"fmt"
+
+	"github.com/0xmukesh/interpreter/internal/diagnostics"
+	"github.com/0xmukesh/interpreter/internal/tokens"
+)
+
+var (
+	UNEXPECTED_CHARACTER_TEMPLATE  = diagnostics.Message{Code: "L001", Text: "Unexpected character: %s"}
+	UNTERMINATED_STRING            = diagnostics.Message{Code: "L002", Text: "Unterminated string."}
+	INVALID_ESCAPE_TEMPLATE        = diagnostics.Message{Code: "L006", Text: `Invalid escape sequence: "\%s".`}
+	INVALID_UNICODE_ESCAPE         = diagnostics.Message{Code: "L007", Text: `Invalid unicode escape, expected "\u{...}" with 1 to 6 hex digits.`}
+	INVALID_CODE_POINT_TEMPLATE    = diagnostics.Message{Code: "L008", Text: "Invalid unicode code point: %s."}
+	UNTERMINATED_RAW_STRING        = diagnostics.Message{Code: "L009", Text: "Unterminated raw string."}
+	UNTERMINATED_INTERPOLATION     = diagnostics.Message{Code: "L010", Text: `Unterminated interpolation, expected "}".`}
+	INT_OUT_OF_RANGE               = diagnostics.Message{Code: "L011", Text: "Integer too large, ints have to fit in 64 bits."}
+	BIG_INT_WITH_FRACTION          = diagnostics.Message{Code: "L012", Text: "Big ints can't have a decimal point or an exponent."}
+	DECIMAL_WITH_EXPONENT          = diagnostics.Message{Code: "L013", Text: "Decimals can't have an exponent, write out their digits instead."}
+	MISSING_FRACTION_DIGITS        = diagnostics.Message{Code: "L014", Text: "Expected digits after the decimal point."}
+	MISSING_EXPONENT_DIGITS        = diagnostics.Message{Code: "L015", Text: "Expected digits in the exponent."}
+	MISSING_DIGITS_TEMPLATE        = diagnostics.Message{Code: "L016", Text: `Expected %s digits after "%s".`}
+	INVALID_DIGIT_TEMPLATE         = diagnostics.Message{Code: "L017", Text: `Invalid digit "%s" in %s number.`}
+	INVALID_UNDERSCORE             = diagnostics.Message{Code: "L018", Text: "Underscores in numbers have to be between digits."}
+	UNEXPECTED_DECIMAL_POINT       = diagnostics.Message{Code: "L019", Text: "Unexpected decimal point after number."}
+	INVALID_NUMBER_SUFFIX_TEMPLATE = diagnostics.Message{Code: "L020", Text: `Invalid suffix "%s" on number.`}
+	FLOAT_OUT_OF_RANGE             = diagnostics.Message{Code: "L021", Text: "Float too large, it doesn't fit in 64 bits."}
+)
+
+const (
+	VALID_ESCAPES_NOTE          = `the escape sequences are \n, \t, \r, \0, \\, \", \', \$ and \u{...}`
+	UNCLOSED_INTERPOLATION_NOTE = "the string is within this interpolation, which isn't closed"
 )
 
 type LexerError struct {
+	Code    string
 	Message string
-	Line    int
+	Span    tokens.Span
+	Notes   []diagnostics.Note
+	// name of the file being lexed, it's empty when the source doesn't come from a file
+	File string
 }
 
-func NewLexerError(msg string, line int) *LexerError {
+func NewLexerError(msg diagnostics.Message, span tokens.Span) *LexerError {
 	return &LexerError{
-		Message: msg,
-		Line:    line,
+		Code:    msg.Code,
+		Message: msg.Text,
+		Span:    span,
 	}
 }
 
+// adds a note to the error, `span` is nil if the note doesn't point at the source
+func (e *LexerError) WithNote(msg string, span *tokens.Span) *LexerError {
+	e.Notes = append(e.Notes, diagnostics.Note{Message: msg, Span: span})
+	return e
+}
+
+func (e LexerError) label() string {
+	return "blud whatcha doing, lexer said nah"
+}
+
 func (e LexerError) Error() string {
-	return fmt.Sprintf("[line: %d] blud whatcha doing, lexer said nah: %s", e.Line, e.Message)
+	return fmt.Sprintf("[%s] %s: %s", e.Span.Start.Format(e.File), e.label(), e.Message)
+}
+
+func (e LexerError) Diagnostic() diagnostics.Diagnostic {
+	return diagnostics.Diagnostic{
+		Severity: diagnostics.ERROR,
+		Code:     e.Code,
+		Message:  e.Message,
+		Label:    e.label(),
+		Span:     e.Span,
+		Notes:    e.Notes,
+	}
 }
@@ -0,0 +1,298 @@
+package lexer
+
+import (
+	"testing"
+
+	"github.com/0xmukesh/interpreter/internal/diagnostics"
+	"github.com/0xmukesh/interpreter/internal/tokens"
+)
+
+// lexes `src` and drops the whitespace and comment tokens
+func lex(t *testing.T, src string) ([]tokens.Token, *LexerError) {
+	t.Helper()
+
+	tkns, err := NewLexer([]byte(src)).LexAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []tokens.Token
+	for _, tkn := range tkns {
+		if tkn.Type != tokens.IGNORE {
+			filtered = append(filtered, tkn)
+		}
+	}
+
+	return filtered, nil
+}
+
+func TestStringEscapes(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{`"plain"`, "plain"},
+		{`"a\nb"`, "a\nb"},
+		{`"tab\there"`, "tab\there"},
+		{`"\r\0"`, "\r\x00"},
+		{`"say \"sup\""`, `say "sup"`},
+		{`"back\\slash"`, `back\slash`},
+		{`"it\'s"`, "it's"},
+		{`"\u{41}\u{e9}\u{1F480}"`, "Aé💀"},
+		{`""`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			tkns, err := lex(t, tt.src)
+			if err != nil {
+				t.Fatalf("unexpected lexer error: %s", err.Error())
+			}
+
+			if len(tkns) != 1 || tkns[0].Type != tokens.STRING {
+				t.Fatalf("got %v, expected a single string token", tkns)
+			}
+
+			if tkns[0].Literal != tt.expected {
+				t.Errorf("got %q, expected %q", tkns[0].Literal, tt.expected)
+			}
+
+			if tkns[0].Lexeme != tt.src {
+				t.Errorf("got lexeme %q, expected the source %q", tkns[0].Lexeme, tt.src)
+			}
+		})
+	}
+}
+
+func TestInvalidStrings(t *testing.T) {
+	tests := []struct {
+		src     string
+		message diagnostics.Message
+		// where the error starts and ends
+		start string
+		end   string
+	}{
+		{`yap("a\qb");`, INVALID_ESCAPE_TEMPLATE.With("q"), "1:7", "1:9"},
+		{`"\u41"`, INVALID_UNICODE_ESCAPE, "1:2", "1:4"},
+		{`"\u{}"`, INVALID_UNICODE_ESCAPE, "1:2", "1:6"},
+		{`"\u{1234567}"`, INVALID_UNICODE_ESCAPE, "1:2", "1:11"},
+		{`"\u{zz}"`, INVALID_UNICODE_ESCAPE, "1:2", "1:5"},
+		{`"\u{D800}"`, INVALID_CODE_POINT_TEMPLATE.With("D800"), "1:2", "1:10"},
+		{"\"abc\nyap(1);", UNTERMINATED_STRING, "1:1", "1:5"},
+		{`"abc\"`, UNTERMINATED_STRING, "1:1", "1:7"},
+		{"rizz a = 1;\n`never\nclosed", UNTERMINATED_RAW_STRING, "2:1", "3:7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := lex(t, tt.src)
+			if err == nil {
+				t.Fatalf("expected a lexer error")
+			}
+
+			if err.Code != tt.message.Code || err.Message != tt.message.Text {
+				t.Errorf("got %s %q, expected %s %q", err.Code, err.Message, tt.message.Code, tt.message.Text)
+			}
+
+			if start, end := err.Span.Start.Format(""), err.Span.End.Format(""); start != tt.start || end != tt.end {
+				t.Errorf("got error at %s-%s, expected %s-%s", start, end, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestInterpolation(t *testing.T) {
+	tkns, err := lex(t, `"a ${x + {"k": 1}["k"]} b ${"c${y}"}\${z}"`)
+	if err != nil {
+		t.Fatalf("unexpected lexer error: %s", err.Error())
+	}
+
+	expected := []struct {
+		tknType tokens.TokenType
+		literal string
+		start   string
+	}{
+		{tokens.STRING_START, "a ", "1:1"},
+		{tokens.IDENTIFIER, "null", "1:6"},
+		{tokens.PLUS, "null", "1:8"},
+		{tokens.LEFT_BRACE, "null", "1:10"},
+		{tokens.STRING, "k", "1:11"},
+		{tokens.COLON, "null", "1:14"},
+		{tokens.NUMBER, "1", "1:16"},
+		{tokens.RIGHT_BRACE, "null", "1:17"},
+		{tokens.LEFT_BRACKET, "null", "1:18"},
+		{tokens.STRING, "k", "1:19"},
+		{tokens.RIGHT_BRACKET, "null", "1:22"},
+		{tokens.STRING_MIDDLE, " b ", "1:23"},
+		{tokens.STRING_START, "c", "1:29"},
+		{tokens.IDENTIFIER, "null", "1:33"},
+		{tokens.STRING_END, "", "1:34"},
+		{tokens.STRING_END, "${z}", "1:36"},
+	}
+
+	if len(tkns) != len(expected) {
+		t.Fatalf("got %d tokens, expected %d: %v", len(tkns), len(expected), tkns)
+	}
+
+	for i, tt := range expected {
+		tkn := tkns[i]
+
+		if tkn.Type != tt.tknType || tkn.Literal != tt.literal || tkn.Span.Start.Format("") != tt.start {
+			t.Errorf("token %d: got %v %q at %s, expected %v %q at %s", i, tkn.Type, tkn.Literal, tkn.Span.Start.Format(""), tt.tknType, tt.literal, tt.start)
+		}
+	}
+}
+
+func TestUnterminatedInterpolation(t *testing.T) {
+	_, err := lex(t, `yap("a ${x + 1");`)
+	if err == nil {
+		t.Fatalf("expected a lexer error")
+	}
+
+	if err.Message != UNTERMINATED_STRING.Text {
+		t.Errorf("got %q, expected %q", err.Message, UNTERMINATED_STRING.Text)
+	}
+
+	if len(err.Notes) != 2 || err.Notes[1].Span == nil || err.Notes[1].Span.Start.Format("") != "1:8" {
+		t.Errorf("got notes %+v, expected the second one to point at the '${' at 1:8", err.Notes)
+	}
+}
+
+func TestNumberLiterals(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{"7", "7"},
+		{"4.25", "4.25"},
+		{"9223372036854775807", "9223372036854775807"},
+		{"99999999999999999999.5", "99999999999999999999.5"},
+		{"99999999999999999999n", "99999999999999999999n"},
+		{"1.10d", "1.10d"},
+		{"3d", "3d"},
+		{"0xff", "255"},
+		{"0XfF", "255"},
+		{"0b1010", "10"},
+		{"0o17", "15"},
+		{"0x7fff_ffff_ffff_ffff", "9223372036854775807"},
+		{"0xffffffffffffffffn", "18446744073709551615n"},
+		{"1_000_000", "1000000"},
+		{"1_000.000_1", "1000.0001"},
+		{"6.02e23", "6.02e23"},
+		{"1E-7", "1e-7"},
+		{"2.5e+3", "2.5e+3"},
+		{"1_234.50d", "1234.50d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			tkns, err := lex(t, tt.src)
+			if err != nil {
+				t.Fatalf("unexpected lexer error: %s", err.Error())
+			}
+
+			if len(tkns) != 1 || tkns[0].Type != tokens.NUMBER || tkns[0].Literal != tt.expected {
+				t.Errorf("got %v, expected a single number token %q", tkns, tt.expected)
+			}
+		})
+	}
+}
+
+func TestInvalidNumbers(t *testing.T) {
+	tests := []struct {
+		src     string
+		message diagnostics.Message
+		// where the error starts and ends
+		start string
+		end   string
+	}{
+		{"yap(9223372036854775808);", INT_OUT_OF_RANGE, "1:5", "1:24"},
+		{"yap(1.5n);", BIG_INT_WITH_FRACTION, "1:5", "1:9"},
+		{"yap(1e5n);", BIG_INT_WITH_FRACTION, "1:5", "1:9"},
+		{"yap(1e5d);", DECIMAL_WITH_EXPONENT, "1:5", "1:9"},
+		{"yap(1.);", MISSING_FRACTION_DIGITS, "1:5", "1:7"},
+		{"yap(1.2.3);", UNEXPECTED_DECIMAL_POINT, "1:8", "1:9"},
+		{"yap(0x1.5);", UNEXPECTED_DECIMAL_POINT, "1:8", "1:9"},
+		{"yap(1e+);", MISSING_EXPONENT_DIGITS, "1:5", "1:8"},
+		{"yap(0x);", MISSING_DIGITS_TEMPLATE.With("hexadecimal", "0x"), "1:5", "1:7"},
+		{"yap(0b102);", INVALID_DIGIT_TEMPLATE.With("2", "binary"), "1:9", "1:10"},
+		{"yap(0o8);", INVALID_DIGIT_TEMPLATE.With("8", "octal"), "1:7", "1:8"},
+		{"yap(1__0);", INVALID_UNDERSCORE, "1:6", "1:7"},
+		{"yap(1_);", INVALID_UNDERSCORE, "1:6", "1:7"},
+		{"yap(0x_1);", MISSING_DIGITS_TEMPLATE.With("hexadecimal", "0x"), "1:5", "1:7"},
+		{"yap(12px);", INVALID_NUMBER_SUFFIX_TEMPLATE.With("px"), "1:7", "1:9"},
+		{"yap(1e400);", FLOAT_OUT_OF_RANGE, "1:5", "1:10"},
+		{"yap(0x1_0000_0000_0000_0000);", INT_OUT_OF_RANGE, "1:5", "1:28"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := lex(t, tt.src)
+			if err == nil {
+				t.Fatalf("expected a lexer error")
+			}
+
+			if err.Code != tt.message.Code || err.Message != tt.message.Text {
+				t.Errorf("got %s %q, expected %s %q", err.Code, err.Message, tt.message.Code, tt.message.Text)
+			}
+
+			if start, end := err.Span.Start.Format(""), err.Span.End.Format(""); start != tt.start || end != tt.end {
+				t.Errorf("got error at %s-%s, expected %s-%s", start, end, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestRawStrings(t *testing.T) {
+	src := "rizz s = `line one\n  \"quoted\" \\n stays\r\nlast`;\nyap(s);"
+
+	tkns, err := lex(t, src)
+	if err != nil {
+		t.Fatalf("unexpected lexer error: %s", err.Error())
+	}
+
+	str := tkns[3]
+	if str.Type != tokens.STRING {
+		t.Fatalf("got %v, expected a string token", str)
+	}
+
+	if expected := "line one\n  \"quoted\" \\n stays\nlast"; str.Literal != expected {
+		t.Errorf("got %q, expected %q", str.Literal, expected)
+	}
+
+	if start, end := str.Span.Start.Format(""), str.Span.End.Format(""); start != "1:10" || end != "3:6" {
+		t.Errorf("got string at %s-%s, expected 1:10-3:6", start, end)
+	}
+
+	// tokens after the string are on the right line
+	yap := tkns[5]
+	if yap.Type != tokens.PRINT || yap.Span.Start.Format("") != "4:1" {
+		t.Errorf("got %v at %s, expected yap at 4:1", yap.Type, yap.Span.Start.Format(""))
+	}
+}
+
+func TestOperators(t *testing.T) {
+	tkns, err := lex(t, "a & b && c | d || e ^ ~f << 2 >> 1 ** 3 * 4 <= 5 >= 6 += -= *= /= %= ++ -- + - / %")
+	if err != nil {
+		t.Fatalf("unexpected lexer error: %s", err.Error())
+	}
+
+	expected := []tokens.TokenType{
+		tokens.IDENTIFIER, tokens.BIT_AND, tokens.IDENTIFIER, tokens.AND, tokens.IDENTIFIER, tokens.BIT_OR,
+		tokens.IDENTIFIER, tokens.OR, tokens.IDENTIFIER, tokens.BIT_XOR, tokens.BIT_NOT, tokens.IDENTIFIER,
+		tokens.LESS_LESS, tokens.NUMBER, tokens.GREATER_GREATER, tokens.NUMBER, tokens.STAR_STAR, tokens.NUMBER,
+		tokens.STAR, tokens.NUMBER, tokens.LESS_EQUAL, tokens.NUMBER, tokens.GREATER_EQUAL, tokens.NUMBER,
+		tokens.PLUS_EQUAL, tokens.MINUS_EQUAL, tokens.STAR_EQUAL, tokens.SLASH_EQUAL, tokens.MODULO_EQUAL,
+		tokens.PLUS_PLUS, tokens.MINUS_MINUS, tokens.PLUS, tokens.MINUS, tokens.SLASH, tokens.MODULO,
+	}
+
+	if len(tkns) != len(expected) {
+		t.Fatalf("got %d tokens, expected %d: %v", len(tkns), len(expected), tkns)
+	}
+
+	for i, tknType := range expected {
+		if tkns[i].Type != tknType {
+			t.Errorf("token %d: got %v, expected %v", i, tkns[i].Type, tknType)
+		}
+	}
+}
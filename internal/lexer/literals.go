@@ -1,18 +1,26 @@
 package lexer
 
 import (
-	"fmt"
+	"math"
+	"math/big"
+	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/0xmukesh/interpreter/internal/tokens"
 	"github.com/0xmukesh/interpreter/internal/utils"
 )
 
+// scans a "..." string. the token's literal is the string's value, with its escape sequences replaced
 func (l *Lexer) LexStrLiterals() (*tokens.Token, *LexerError) {
-	strLiteral := ""
-	strLiteral += string(l.Char)
-	closingQuoteFound := false
+	return l.lexStrSegment(false)
+}
+
+// scans the text of a "..." string up to its closing quote or up to the "${" of an interpolation.
+// `afterInterpolation` is set when the text follows the "}" closing an interpolation
+func (l *Lexer) lexStrSegment(afterInterpolation bool) (*tokens.Token, *LexerError) {
+	var value strings.Builder
 
 	for {
 		nextChar := l.peek()
@@ -23,58 +31,349 @@ func (l *Lexer) LexStrLiterals() (*tokens.Token, *LexerError) {
 
 		l.read()
 
-		strLiteral += string(l.Char)
-
 		if l.Char == '"' {
-			closingQuoteFound = true
-			break
+			tknType := tokens.STRING
+			if afterInterpolation {
+				tknType = tokens.STRING_END
+			}
+
+			return tokens.NewToken(tknType, string(l.Src[l.start.Offset:l.Idx]), value.String(), l.span()), nil
+		}
+
+		if l.Char == '$' && l.peek() == '{' {
+			l.interpolations = append(l.interpolations, interpolation{start: l.position()})
+			l.read()
+
+			tknType := tokens.STRING_START
+			if afterInterpolation {
+				tknType = tokens.STRING_MIDDLE
+			}
+
+			return tokens.NewToken(tknType, string(l.Src[l.start.Offset:l.Idx]), value.String(), l.span()), nil
 		}
+
+		if l.Char == '\\' {
+			if err := l.lexEscape(&value); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		value.WriteByte(l.Char)
 	}
 
-	if !closingQuoteFound {
-		return nil, NewLexerError("Unterminated string.", l.Line)
+	err := NewLexerError(UNTERMINATED_STRING, l.span()).WithNote("a string has to be closed on the line it starts on, use a `...` string for text spanning lines", nil)
+
+	// the string might be the closing quote of an interpolation's string which was meant to end
+	// the interpolation
+	if len(l.interpolations) > 0 {
+		opening := l.interpolations[len(l.interpolations)-1].span()
+		err.WithNote(UNCLOSED_INTERPOLATION_NOTE, &opening)
 	}
 
-	return tokens.NewToken(tokens.STRING, strLiteral, strLiteral[1:len(strLiteral)-1], l.Line), nil
+	return nil, err
 }
 
-func (l *Lexer) LexNumLiterals() (*tokens.Token, *LexerError) {
-	numLiteral := ""
-	numLiteral += string(l.Char)
-	decimalPointFound := false
+var escapes = map[byte]byte{
+	'n':  '\n',
+	't':  '\t',
+	'r':  '\r',
+	'0':  0,
+	'\\': '\\',
+	'"':  '"',
+	'\'': '\'',
+	'$':  '$',
+}
+
+// writes the character the escape sequence starting at the "\\" in `Char` stands for to `value`
+func (l *Lexer) lexEscape(value *strings.Builder) *LexerError {
+	start := l.position()
+
+	nextChar := l.peek()
+	if nextChar == '\n' || nextChar == 0 {
+		// the string is unterminated, which is reported by the caller
+		return nil
+	}
+
+	l.read()
+
+	if char, ok := escapes[l.Char]; ok {
+		value.WriteByte(char)
+		return nil
+	}
+
+	if l.Char != 'u' {
+		return NewLexerError(INVALID_ESCAPE_TEMPLATE.With(string(l.Char)), l.spanFrom(start)).WithNote(VALID_ESCAPES_NOTE, nil)
+	}
+
+	if l.peek() != '{' {
+		return NewLexerError(INVALID_UNICODE_ESCAPE, l.spanFrom(start))
+	}
+
+	l.read()
+
+	digits := ""
+	for l.peek() != '}' {
+		if !isHexDigit(l.peek()) || len(digits) == 6 {
+			return NewLexerError(INVALID_UNICODE_ESCAPE, l.spanFrom(start))
+		}
+
+		l.read()
+		digits += string(l.Char)
+	}
 
+	l.read()
+
+	if digits == "" {
+		return NewLexerError(INVALID_UNICODE_ESCAPE, l.spanFrom(start))
+	}
+
+	codePoint, _ := strconv.ParseUint(digits, 16, 32)
+	if !utf8.ValidRune(rune(codePoint)) {
+		return NewLexerError(INVALID_CODE_POINT_TEMPLATE.With(digits), l.spanFrom(start))
+	}
+
+	value.WriteRune(rune(codePoint))
+	return nil
+}
+
+func isHexDigit(char byte) bool {
+	return (char >= '0' && char <= '9') || (char >= 'a' && char <= 'f') || (char >= 'A' && char <= 'F')
+}
+
+// scans a `...` string. it can span lines and has no escape sequences, its value is the text as is
+func (l *Lexer) LexRawStrLiterals() (*tokens.Token, *LexerError) {
 	for {
-		nextChar := l.peek()
+		if l.peek() == 0 {
+			return nil, NewLexerError(UNTERMINATED_RAW_STRING, l.span())
+		}
 
-		if !(unicode.IsDigit(rune(nextChar)) || nextChar == '.') {
+		l.read()
+
+		if l.Char == '`' {
 			break
 		}
+	}
 
-		if nextChar == '.' {
-			if decimalPointFound {
-				return nil, NewLexerError("Unterminated number.", l.Line)
-			}
-			decimalPointFound = true
+	lexeme := string(l.Src[l.start.Offset:l.Idx])
+	// the value doesn't depend on the line breaks the file was saved with
+	value := strings.ReplaceAll(lexeme[1:len(lexeme)-1], "\r\n", "\n")
+
+	return tokens.NewToken(tokens.STRING, lexeme, value, l.span()), nil
+}
+
+var radixPrefixes = map[byte]struct {
+	radix int
+	name  string
+}{
+	'x': {16, "hexadecimal"},
+	'b': {2, "binary"},
+	'o': {8, "octal"},
+}
+
+// scans a number such as 42, 1_000, 4.2, 6.02e23, 0xff, 0b1010 or 0o17. "n" after the digits makes
+// it a big int and "d" makes it a decimal. the token's literal is the number in base 10 without
+// underscores, followed by its suffix
+func (l *Lexer) LexNumLiterals() (*tokens.Token, *LexerError) {
+	if l.Char == '0' {
+		if prefix, ok := radixPrefixes[byte(unicode.ToLower(rune(l.peek())))]; ok {
+			return l.lexRadixNumLiteral(prefix.radix, prefix.name)
+		}
+	}
+
+	literal, err := l.lexDigits(string(l.Char), 10, "decimal")
+	if err != nil {
+		return nil, err
+	}
+
+	isFloat := false
+	hasExponent := false
+
+	if l.peek() == '.' {
+		l.read()
+
+		if !isDecimalDigit(l.peek()) {
+			return nil, NewLexerError(MISSING_FRACTION_DIGITS, l.span())
+		}
+
+		fraction, err := l.lexDigits("", 10, "decimal")
+		if err != nil {
+			return nil, err
+		}
+
+		literal += "." + fraction
+		isFloat = true
+	}
+
+	if nextChar := l.peek(); nextChar == 'e' || nextChar == 'E' {
+		l.read()
+		literal += "e"
+
+		if nextChar := l.peek(); nextChar == '+' || nextChar == '-' {
+			l.read()
+			literal += string(l.Char)
+		}
+
+		if !isDecimalDigit(l.peek()) {
+			return nil, NewLexerError(MISSING_EXPONENT_DIGITS, l.span())
+		}
+
+		exponent, err := l.lexDigits("", 10, "decimal")
+		if err != nil {
+			return nil, err
+		}
+
+		literal += exponent
+		hasExponent = true
+	}
+
+	switch l.peek() {
+	case 'n':
+		l.read()
+
+		if isFloat || hasExponent {
+			return nil, NewLexerError(BIG_INT_WITH_FRACTION, l.span()).WithNote("use a decimal, such as 1.5d, for numbers with a fraction", nil)
 		}
 
+		literal += "n"
+	case 'd':
 		l.read()
 
-		numLiteral += string(l.Char)
+		if hasExponent {
+			return nil, NewLexerError(DECIMAL_WITH_EXPONENT, l.span())
+		}
+
+		literal += "d"
+	default:
+		if err := l.checkNumRange(literal, isFloat || hasExponent); err != nil {
+			return nil, err
+		}
 	}
 
-	if strings.HasSuffix(numLiteral, ".") {
-		return nil, NewLexerError("Unterminated number.", l.Line)
+	if err := l.checkNumEnd(); err != nil {
+		return nil, err
+	}
+
+	return tokens.NewToken(tokens.NUMBER, string(l.Src[l.start.Offset:l.Idx]), literal, l.span()), nil
+}
+
+// scans a number after its "0" when it's followed by the "x", "b" or "o" of its radix
+func (l *Lexer) lexRadixNumLiteral(radix int, name string) (*tokens.Token, *LexerError) {
+	l.read()
+
+	if !isHexDigit(l.peek()) {
+		return nil, NewLexerError(MISSING_DIGITS_TEMPLATE.With(name, string(l.Src[l.start.Offset:l.Idx])), l.span())
+	}
+
+	digits, err := l.lexDigits("", radix, name)
+	if err != nil {
+		return nil, err
 	}
 
 	var literal string
 
-	if !strings.Contains(numLiteral, ".") {
-		literal = fmt.Sprintf("%s.%d", numLiteral, 0)
+	if l.peek() == 'n' {
+		l.read()
+
+		num, _ := new(big.Int).SetString(digits, radix)
+		literal = num.String() + "n"
 	} else {
-		literal = numLiteral
+		num, err := strconv.ParseInt(digits, radix, 64)
+		if err != nil {
+			return nil, NewLexerError(INT_OUT_OF_RANGE, l.span()).WithNote("use a big int, such as 0xffn, for larger whole numbers", nil)
+		}
+
+		literal = strconv.FormatInt(num, 10)
+	}
+
+	if err := l.checkNumEnd(); err != nil {
+		return nil, err
+	}
+
+	return tokens.NewToken(tokens.NUMBER, string(l.Src[l.start.Offset:l.Idx]), literal, l.span()), nil
+}
+
+// reads the digits after `digits`, which can be separated by underscores, and returns all of them
+// without the underscores. digits of radixes other than 10 are read as hex digits, so a digit too
+// large for the radix is reported rather than left for the next token
+func (l *Lexer) lexDigits(digits string, radix int, name string) (string, *LexerError) {
+	isScanned := isDecimalDigit
+	if radix != 10 {
+		isScanned = isHexDigit
+	}
+
+	for {
+		nextChar := l.peek()
+
+		if nextChar == '_' {
+			l.read()
+
+			if digits == "" || !isScanned(l.peek()) {
+				return "", NewLexerError(INVALID_UNDERSCORE, l.spanFrom(l.position()))
+			}
+
+			continue
+		}
+
+		if !isScanned(nextChar) {
+			return digits, nil
+		}
+
+		l.read()
+
+		if value, _ := strconv.ParseUint(string(l.Char), 16, 8); int(value) >= radix {
+			return "", NewLexerError(INVALID_DIGIT_TEMPLATE.With(string(l.Char), name), l.spanFrom(l.position()))
+		}
+
+		digits += string(l.Char)
+	}
+}
+
+// checks that an int fits in 64 bits and that a float isn't too large to be represented
+func (l *Lexer) checkNumRange(literal string, isFloat bool) *LexerError {
+	if !isFloat {
+		if _, err := strconv.ParseInt(literal, 10, 64); err != nil {
+			return NewLexerError(INT_OUT_OF_RANGE, l.span()).WithNote("use a big int, such as 123n, for larger whole numbers", nil)
+		}
+
+		return nil
 	}
 
-	return tokens.NewToken(tokens.NUMBER, numLiteral, literal, l.Line), nil
+	if num, _ := strconv.ParseFloat(literal, 64); math.IsInf(num, 0) {
+		return NewLexerError(FLOAT_OUT_OF_RANGE, l.span())
+	}
+
+	return nil
+}
+
+// reports letters, digits or a decimal point right after a number, such as the "px" of 12px
+func (l *Lexer) checkNumEnd() *LexerError {
+	if l.peek() == '.' {
+		l.read()
+		return NewLexerError(UNEXPECTED_DECIMAL_POINT, l.spanFrom(l.position()))
+	}
+
+	if !isIdentifierChar(l.peek()) {
+		return nil
+	}
+
+	l.read()
+	start := l.position()
+
+	for isIdentifierChar(l.peek()) {
+		l.read()
+	}
+
+	return NewLexerError(INVALID_NUMBER_SUFFIX_TEMPLATE.With(string(l.Src[start.Offset:l.Idx])), l.spanFrom(start))
+}
+
+func isDecimalDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
+
+func isIdentifierChar(char byte) bool {
+	return char == '_' || isDecimalDigit(char) || unicode.IsLetter(rune(char))
 }
 
 func (l *Lexer) LexIdentifier() (*tokens.Token, *LexerError) {
@@ -96,8 +395,8 @@ func (l *Lexer) LexIdentifier() (*tokens.Token, *LexerError) {
 
 	identifierType, isReserved := utils.HasValueMap(tokens.ReservedKeywordsMapping, strings.ToUpper(identLiteral))
 	if isReserved {
-		return tokens.NewToken(*identifierType, identLiteral, "null", l.Line), nil
+		return tokens.NewToken(*identifierType, identLiteral, "null", l.span()), nil
 	}
 
-	return tokens.NewToken(tokens.IDENTIFIER, identLiteral, "null", l.Line), nil
+	return tokens.NewToken(tokens.IDENTIFIER, identLiteral, "null", l.span()), nil
 }
@@ -6,18 +6,302 @@ import (
 )
 
 func (p *Parser) parseGroupingExpr() (*ast.AstNode, *ParserError) {
+	start := p.curr()
+
 	nodePtr, err := p.Parse()
 	if err != nil {
 		return nil, err
 	}
 
 	if nodePtr != nil {
-		err := NewParserError(MISSING_RPAREN, p.curr().Lexeme, p.curr().Line)
-		p.consume(tokens.RIGHT_PAREN, *err)
+		if err := p.consume(tokens.RIGHT_PAREN, p.unclosedError(MISSING_RPAREN, start)); err != nil {
+			return nil, err
+		}
 
 		node := *nodePtr
-		return ast.NewAstNode(ast.EXPR, ast.NewGroupingExpr(node, p.curr().Line)), nil
+		return ast.NewAstNode(ast.EXPR, ast.NewGroupingExpr(node, p.spanFrom(start))), nil
+	}
+
+	return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
+}
+
+// [...elements]
+func (p *Parser) parseListExpr() (*ast.AstNode, *ParserError) {
+	start := p.curr()
+	var elements []ast.AstNode
+
+	if p.peek().Type != tokens.RIGHT_BRACKET {
+		// equivalent to do-while loop in java
+		for ok := true; ok; ok = p.matchAndAdvance(tokens.COMMA) {
+			node, err := p.Parse()
+			if err != nil {
+				return nil, err
+			}
+
+			if node == nil {
+				return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
+			}
+
+			elements = append(elements, *node)
+		}
+	}
+
+	if err := p.consume(tokens.RIGHT_BRACKET, p.unclosedError(MISSING_RBRACKET, start)); err != nil {
+		return nil, err
+	}
+
+	return ast.NewAstNode(ast.EXPR, ast.NewListExpr(elements, p.spanFrom(start))), nil
+}
+
+// the lexer splits an interpolated string around its expressions, so it's a STRING_START token
+// followed by every expression and the STRING_MIDDLE or STRING_END token which comes after it
+func (p *Parser) parseInterpolatedStrExpr() (*ast.AstNode, *ParserError) {
+	start := p.curr()
+	var parts []ast.AstNode
+
+	for {
+		if text := p.curr(); text.Literal != "" {
+			parts = append(parts, *ast.NewAstNode(ast.EXPR, ast.NewLiteralExpr(tokens.STRING, text.Literal, text.Span)))
+		}
+
+		if p.curr().Type == tokens.STRING_END {
+			break
+		}
+
+		// the "${" at the end of the text
+		end := p.curr().Span.End
+		opening := *tokens.NewToken(tokens.STRING_START, "${", "", tokens.Span{
+			Start: tokens.Position{Offset: end.Offset - 2, Line: end.Line, Column: end.Column - 2},
+			End:   end,
+		})
+
+		node, err := p.Parse()
+		if err != nil {
+			return nil, err
+		}
+
+		if node == nil {
+			return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
+		}
+
+		if _, err := p.extractExpr(*node); err != nil {
+			return nil, err
+		}
+
+		parts = append(parts, *node)
+
+		if !p.matchAndAdvance(tokens.STRING_MIDDLE, tokens.STRING_END) {
+			return nil, p.unclosedError(MISSING_RBRACE, opening)
+		}
+	}
+
+	return ast.NewAstNode(ast.EXPR, ast.NewInterpolatedStrExpr(parts, p.spanFrom(start))), nil
+}
+
+// (node)[index]
+func (p *Parser) parseIndexExpr(node ast.AstNode) (*ast.AstNode, *ParserError) {
+	// checking whether next token is "[" or not is handled by `postfixRule`
+	p.advance()
+	lbracket := p.curr()
+
+	indexNode, err := p.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	if indexNode == nil {
+		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
+	}
+
+	if err := p.consume(tokens.RIGHT_BRACKET, p.unclosedError(MISSING_RBRACKET, lbracket)); err != nil {
+		return nil, err
+	}
+
+	return ast.NewAstNode(ast.EXPR, ast.NewIndexExpr(node, *indexNode, node.Value.GetSpan().To(p.curr().Span))), nil
+}
+
+// {...(key): (value)}
+func (p *Parser) parseMapExpr() (*ast.AstNode, *ParserError) {
+	start := p.curr()
+	var entries []ast.MapEntry
+
+	if p.peek().Type != tokens.RIGHT_BRACE {
+		// equivalent to do-while loop in java
+		for ok := true; ok; ok = p.matchAndAdvance(tokens.COMMA) {
+			keyNode, err := p.Parse()
+			if err != nil {
+				return nil, err
+			}
+
+			if keyNode == nil {
+				return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
+			}
+
+			if err := p.consume(tokens.COLON, NewParserError(MISSING_COLON, p.curr().Lexeme, p.curr().Span)); err != nil {
+				return nil, err
+			}
+
+			valueNode, err := p.Parse()
+			if err != nil {
+				return nil, err
+			}
+
+			if valueNode == nil {
+				return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
+			}
+
+			entries = append(entries, ast.NewMapEntry(*keyNode, *valueNode))
+		}
+	}
+
+	if err := p.consume(tokens.RIGHT_BRACE, p.unclosedError(MISSING_RBRACE, start)); err != nil {
+		return nil, err
+	}
+
+	return ast.NewAstNode(ast.EXPR, ast.NewMapExpr(entries, p.spanFrom(start))), nil
+}
+
+// (callee)(...args)
+//
+// `callee` is any expression evaluating to a function, so calls can be chained like `makeCounter()()`.
+// a call is an expression, so it can be an operand anywhere, like `f(1) + g(2)`
+func (p *Parser) parseCallExpr(callee ast.AstNode) (*ast.AstNode, *ParserError) {
+	// checking whether next token is "(" or not is handled by `postfixRule`
+	p.advance()
+	lparen := p.curr()
+
+	var args []ast.AstNode
+
+	if p.peek().Type != tokens.RIGHT_PAREN {
+		// equivalent to do-while loop in java
+		for ok := true; ok; ok = p.matchAndAdvance(tokens.COMMA) {
+			node, err := p.Parse()
+			if err != nil {
+				return nil, err
+			}
+
+			if node == nil {
+				return nil, NewParserError(INVALID_EXPRESSION, p.curr().Lexeme, p.curr().Span)
+			}
+
+			args = append(args, *node)
+		}
+	}
+
+	if len(args) > 255 {
+		return nil, NewParserError(TOO_MANY_ARGUMENTS, p.curr().Lexeme, p.curr().Span)
+	}
+
+	if err := p.consume(tokens.RIGHT_PAREN, p.unclosedError(MISSING_RPAREN, lparen)); err != nil {
+		return nil, err
+	}
+
+	return ast.NewAstNode(ast.EXPR, ast.NewCallExpr(callee, args, callee.Value.GetSpan().To(p.curr().Span))), nil
+}
+
+// (name)++ and (name)--. the operator has to be on the same line as its operand, otherwise it's
+// the prefix operator of the next line. `node` is returned as is if no such operator follows it
+func (p *Parser) parsePostfixIncrementExpr(node ast.AstNode) (*ast.AstNode, *ParserError) {
+	operator := p.peek()
+
+	if _, isExpr := node.Value.(ast.Expr); !isExpr || p.isAtEnd() || !p.isSameLine() {
+		return &node, nil
+	}
+
+	if operator.Type != tokens.PLUS_PLUS && operator.Type != tokens.MINUS_MINUS {
+		return &node, nil
+	}
+
+	p.advance()
+
+	target, err := p.assignmentTarget(node, operator)
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewAstNode(ast.EXPR, ast.NewIncrementExpr(target.Value, operator.Type, false, target.Span.To(operator.Span))), nil
+}
+
+// (target) = (value), where the target is a variable or an index expression. `target` is the whole
+// expression on the left of the "=", which is the next token. the value is parsed as a whole
+// expression, so `a = b = 1` is `a = (b = 1)`
+func (p *Parser) parseAssignExpr(target ast.AstNode) (*ast.AstNode, *ParserError) {
+	p.advance()
+	operator := p.curr()
+
+	indexExpr, isIndex := target.Value.(ast.IndexExpr)
+	if !isIndex {
+		if _, err := p.assignmentTarget(target, operator); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.isAtEnd() {
+		return nil, NewParserError(EXPRESSION_AFTER_ASSIGNMENT_EXPECTED, p.curr().Lexeme, p.curr().Span)
+	}
+
+	p.advance()
+
+	valueNode, err := p.expressionRule(LOWEST)
+	if err != nil {
+		return nil, err
+	}
+
+	if valueNode == nil {
+		return nil, NewParserError(EXPRESSION_AFTER_ASSIGNMENT_EXPECTED, p.curr().Lexeme, p.curr().Span)
+	}
+
+	value, err := p.extractExpr(*valueNode)
+	if err != nil {
+		return nil, err
+	}
+
+	if isIndex {
+		return ast.NewAstNode(ast.EXPR, ast.NewIndexAssignExpr(indexExpr, value, indexExpr.Span.To(value.GetSpan()))), nil
+	}
+
+	variable := target.Value.(ast.LiteralExpr)
+	return ast.NewAstNode(ast.EXPR, ast.NewAssignExpr(variable.Value, value, variable.Span.To(value.GetSpan()))), nil
+}
+
+// (name) += (value). `target` is the whole expression on the left of the operator, which is the next
+// token. the value is parsed as a whole expression, so `a += b += 1` is `a += (b += 1)`
+func (p *Parser) parseCompoundAssignExpr(target ast.AstNode, operator tokens.TokenType) (*ast.AstNode, *ParserError) {
+	p.advance()
+
+	variable, err := p.assignmentTarget(target, p.curr())
+	if err != nil {
+		return nil, err
+	}
+
+	if p.isAtEnd() || !p.isSameLine() {
+		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
+	}
+
+	p.advance()
+
+	valueNode, err := p.expressionRule(LOWEST)
+	if err != nil {
+		return nil, err
+	}
+
+	if valueNode == nil {
+		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
+	}
+
+	value, err := p.extractExpr(*valueNode)
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewAstNode(ast.EXPR, ast.NewCompoundAssignExpr(variable.Value, operator, value, variable.Span.To(value.GetSpan()))), nil
+}
+
+// returns the variable `operator` updates, which is the only kind of expression it can update
+func (p *Parser) assignmentTarget(target ast.AstNode, operator tokens.Token) (ast.LiteralExpr, *ParserError) {
+	if literal, ok := target.Value.(ast.LiteralExpr); ok && literal.TokenType == tokens.IDENTIFIER {
+		return literal, nil
 	}
 
-	return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
+	return ast.LiteralExpr{}, NewParserError(INVALID_ASSIGNMENT_TARGET_TEMPLATE.With(operator.Lexeme), operator.Lexeme, operator.Span)
 }
@@ -1,15 +1,15 @@
 package parser
 
 import (
-	"fmt"
 	"slices"
 
 	"github.com/0xmukesh/interpreter/internal/ast"
 	"github.com/0xmukesh/interpreter/internal/tokens"
-	"github.com/0xmukesh/interpreter/internal/utils"
 )
 
 func (p *Parser) parsePrintStmt() (*ast.AstNode, *ParserError) {
+	start := p.curr()
+
 	nodePtr, err := p.Parse()
 	if err != nil {
 		return nil, err
@@ -17,31 +17,41 @@ func (p *Parser) parsePrintStmt() (*ast.AstNode, *ParserError) {
 
 	if nodePtr != nil {
 		node := *nodePtr
-		err = NewParserError(MISSING_SEMICOLON, p.curr().Lexeme, p.curr().Line)
-		p.consume(tokens.SEMICOLON, *err)
-		return ast.NewAstNode(ast.STMT, ast.NewPrintStmt(node, p.curr().Line)), nil
+		if err := p.consume(tokens.SEMICOLON, NewParserError(MISSING_SEMICOLON, p.curr().Lexeme, p.curr().Span)); err != nil {
+			return nil, err
+		}
+		return ast.NewAstNode(ast.STMT, ast.NewPrintStmt(node, p.spanFrom(start))), nil
 	} else {
-		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
+		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
 	}
 }
 
 func (p *Parser) parseVarAssignStmt() (*ast.AstNode, *ParserError) {
-	varNameNode, err := p.Parse()
+	start := p.curr()
+
+	if p.isAtEnd() {
+		return nil, NewParserError(VARIABLE_NAME_EXPECTED, p.curr().Lexeme, p.curr().Span)
+	}
+
+	// only the name is parsed, otherwise the "=" after it would be taken for an assignment
+	p.advance()
+
+	varNameNode, err := p.primaryRule()
 	if err != nil {
 		return nil, err
 	}
 	if varNameNode == nil {
-		return nil, NewParserError(VARIABLE_NAME_EXPECTED, p.curr().Lexeme, p.curr().Line)
+		return nil, NewParserError(VARIABLE_NAME_EXPECTED, p.curr().Lexeme, p.curr().Span)
 	}
 
 	varNameExpr := varNameNode.ExtractExpr()
 	if varNameExpr == nil {
-		return nil, NewParserError(INVALID_VARIABLE_NAME, p.curr().Lexeme, p.curr().Line)
+		return nil, NewParserError(INVALID_VARIABLE_NAME, p.curr().Lexeme, p.curr().Span)
 	}
 
 	varNameLiteralExpr, isLiteralExpr := varNameExpr.(ast.LiteralExpr)
 	if !isLiteralExpr {
-		return nil, NewParserError(INVALID_VARIABLE_NAME, p.curr().Lexeme, p.curr().Line)
+		return nil, NewParserError(INVALID_VARIABLE_NAME, p.curr().Lexeme, p.curr().Span)
 	}
 
 	varName := varNameLiteralExpr.Value
@@ -57,18 +67,21 @@ func (p *Parser) parseVarAssignStmt() (*ast.AstNode, *ParserError) {
 		}
 
 		if varValueNode == nil {
-			return nil, NewParserError(EXPRESSION_AFTER_ASSIGNMENT_EXPECTED, p.curr().Lexeme, p.curr().Line)
+			return nil, NewParserError(EXPRESSION_AFTER_ASSIGNMENT_EXPECTED, p.curr().Lexeme, p.curr().Span)
 		}
 	} else {
-		varValueNode = ast.NewAstNode(ast.EXPR, ast.NewLiteralExpr(tokens.NIL, "", p.curr().Line))
+		varValueNode = ast.NewAstNode(ast.EXPR, ast.NewLiteralExpr(tokens.NIL, "", p.curr().Span))
 	}
 
-	err = NewParserError(MISSING_SEMICOLON, p.curr().Lexeme, p.curr().Line)
-	p.consume(tokens.SEMICOLON, *err)
-	return ast.NewAstNode(ast.STMT, ast.NewVarAssignStmt(varName, *varValueNode, p.curr().Line)), nil
+	if err := p.consume(tokens.SEMICOLON, NewParserError(MISSING_SEMICOLON, p.curr().Lexeme, p.curr().Span)); err != nil {
+		return nil, err
+	}
+	return ast.NewAstNode(ast.STMT, ast.NewVarAssignStmt(varName, *varValueNode, p.spanFrom(start))), nil
 }
 
 func (p *Parser) parseCreateBlockStmt() (*ast.AstNode, *ParserError) {
+	start := p.curr()
+
 	rBraceFound := false
 	var nodes []ast.AstNode
 
@@ -77,14 +90,16 @@ func (p *Parser) parseCreateBlockStmt() (*ast.AstNode, *ParserError) {
 
 		if nextTkn.Type == tokens.RIGHT_BRACE {
 			p.advance()
-			nodes = append(nodes, *ast.NewAstNode(ast.STMT, ast.NewCloseBlockStmt(p.curr().Line)))
+			nodes = append(nodes, *ast.NewAstNode(ast.STMT, ast.NewCloseBlockStmt(p.curr().Span)))
 			rBraceFound = true
 			break
 		}
 
+		// the rest of the block is still parsed after an error, `BuildAst` reports it
 		node, err := p.Parse()
 		if err != nil {
-			return nil, err
+			p.recover(err)
+			continue
 		}
 
 		if node != nil {
@@ -93,33 +108,53 @@ func (p *Parser) parseCreateBlockStmt() (*ast.AstNode, *ParserError) {
 	}
 
 	if !rBraceFound {
-		return nil, NewParserError(MISSING_RBRACE, p.curr().Lexeme, p.curr().Line)
+		return nil, p.unclosedError(MISSING_RBRACE, start)
+	}
+
+	return ast.NewAstNode(ast.STMT, ast.NewCreateBlockStmt(nodes, p.spanFrom(start))), nil
+}
+
+// parses the body of a statement. a body starting with "{" is always a block, even if it
+// looks like a map literal (e.g. an empty body)
+func (p *Parser) parseBody() (*ast.AstNode, *ParserError) {
+	if p.peek().Type == tokens.LEFT_BRACE {
+		p.advance()
+		return p.parseCreateBlockStmt()
 	}
 
-	return ast.NewAstNode(ast.STMT, ast.NewCreateBlockStmt(nodes, p.curr().Line)), nil
+	return p.Parse()
+}
+
+func (p *Parser) parseLoopBody() (*ast.AstNode, *ParserError) {
+	p.loopDepth++
+	defer func() { p.loopDepth-- }()
+
+	return p.parseBody()
 }
 
 func (p *Parser) parseIfStmt() (*ast.AstNode, *ParserError) {
+	start := p.curr()
+
 	ifConditionNode, err := p.Parse()
 	if err != nil {
 		return nil, err
 	}
 
 	if ifConditionNode == nil {
-		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
+		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
 	}
 
 	if !ifConditionNode.Value.IsExpr() {
-		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
+		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
 	}
 
-	ifBranch, err := p.Parse()
+	ifBranch, err := p.parseBody()
 	if err != nil {
 		return nil, err
 	}
 
 	if ifBranch == nil {
-		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
+		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
 	}
 
 	var elseIfStmts []ast.ElseIfStmt
@@ -127,6 +162,7 @@ func (p *Parser) parseIfStmt() (*ast.AstNode, *ParserError) {
 
 	for p.peek().Type == tokens.ELSE_IF {
 		p.advance()
+		elseIfStart := p.curr()
 
 		elseIfConditionNode, err := p.Parse()
 		if err != nil {
@@ -134,230 +170,197 @@ func (p *Parser) parseIfStmt() (*ast.AstNode, *ParserError) {
 		}
 
 		if elseIfConditionNode == nil {
-			return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
+			return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
 		}
 
 		if !elseIfConditionNode.Value.IsExpr() {
-			return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
+			return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
 		}
 
-		elseIfBranch, err := p.Parse()
+		elseIfBranch, err := p.parseBody()
 		if err != nil {
 			return nil, err
 		}
 
 		if elseIfBranch == nil {
-			return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
+			return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
 		}
 
-		elseIfStmts = append(elseIfStmts, ast.NewElseIfStmt(*elseIfConditionNode, *elseIfBranch, p.curr().Line))
+		elseIfStmts = append(elseIfStmts, ast.NewElseIfStmt(*elseIfConditionNode, *elseIfBranch, p.spanFrom(elseIfStart)))
 	}
 
 	if p.peek().Type == tokens.ELSE {
 		p.advance()
+		elseStart := p.curr()
 
-		elseBranch, err := p.Parse()
+		elseBranch, err := p.parseBody()
 		if err != nil {
 			return nil, err
 		}
 
 		if elseBranch == nil {
-			return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
+			return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
 		}
 
-		elseStmt = ast.NewElseStmt(*elseBranch, p.curr().Line)
+		elseStmt = ast.NewElseStmt(*elseBranch, p.spanFrom(elseStart))
 	}
 
-	return ast.NewAstNode(ast.STMT, ast.NewIfStmt(*ifConditionNode, *ifBranch, &elseIfStmts, &elseStmt, p.curr().Line)), nil
+	return ast.NewAstNode(ast.STMT, ast.NewIfStmt(*ifConditionNode, *ifBranch, &elseIfStmts, &elseStmt, p.spanFrom(start))), nil
 }
 
-func (p *Parser) parseVarReassignStmt() (*ast.AstNode, *ParserError) {
-	varName := p.curr().Lexeme
-	// checking whether next token is "=" or not is handled within the switch-case statement
-	p.advance()
+func (p *Parser) parseWhileStmt() (*ast.AstNode, *ParserError) {
+	start := p.curr()
 
-	varValueNode, err := p.Parse()
+	node, err := p.Parse()
 	if err != nil {
-		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
+		return nil, err
 	}
 
-	if varValueNode == nil {
-		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
+	if node == nil {
+		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
 	}
 
-	return ast.NewAstNode(ast.STMT, ast.NewVarReassignStmt(varName, *varValueNode, p.curr().Line)), nil
-}
-
-func (p *Parser) parseWhileStmt() (*ast.AstNode, *ParserError) {
-	node, err := p.Parse()
-	if err != nil || node == nil {
-		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
+	branch, err := p.parseLoopBody()
+	if err != nil {
+		return nil, err
 	}
 
-	branch, err := p.Parse()
-	if err != nil || branch == nil {
-		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
+	if branch == nil {
+		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
 	}
 
-	return ast.NewAstNode(ast.STMT, ast.NewWhileStmt(*node, *branch, p.curr().Line)), nil
+	return ast.NewAstNode(ast.STMT, ast.NewWhileStmt(*node, *branch, p.spanFrom(start))), nil
 }
 
+//	skibidi (name)(...args) {
+//	  ...node
+//	}
+//
+// a `skibidi` without a name is parsed as an anonymous function expression
 func (p *Parser) parseFuncDeclarationStmt() (*ast.AstNode, *ParserError) {
-	funcName, err := p.Parse()
-	if err != nil || funcName == nil || funcName.ExtractExpr() == nil {
-		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
-	}
+	start := p.curr()
 
-	literalExpr, isLiteralExpr := funcName.ExtractExpr().(ast.LiteralExpr)
-
-	if !isLiteralExpr {
-		return nil, NewParserError(INVALID_EXPRESSION, p.curr().Lexeme, p.curr().Line)
+	if p.peek().Type == tokens.LEFT_PAREN {
+		return p.parseFuncExpr()
 	}
 
-	if literalExpr.TokenType != tokens.IDENTIFIER {
-		return nil, NewParserError(INVALID_EXPRESSION, p.curr().Lexeme, p.curr().Line)
+	if !p.matchAndAdvance(tokens.IDENTIFIER) {
+		return nil, NewParserError(INVALID_TOKEN_TYPE_TEMPLATE.With(tokens.IDENTIFIER.String()), p.peek().Lexeme, p.peek().Span)
 	}
 
-	p.consume(tokens.LEFT_PAREN, *NewParserError(MISSING_LPAREN, p.curr().Lexeme, p.curr().Line))
-
-	var args []ast.AstNode
-
-	if p.peek().Type != tokens.RIGHT_PAREN {
-		// equivalent to do-while loop in java
-		for ok := true; ok; ok = p.matchAndAdvance(tokens.COMMA) {
-			node, err := p.Parse()
-			if err != nil || node == nil {
-				return nil, NewParserError(INVALID_EXPRESSION, p.curr().Lexeme, p.curr().Line)
-			}
+	funcName := p.curr().Lexeme
 
-			args = append(args, *node)
-		}
+	args, nodeTbe, err := p.parseFuncSignatureAndBody()
+	if err != nil {
+		return nil, err
 	}
 
-	if len(args) >= 255 {
-		return nil, NewParserError("can't have more than 255 arguments", p.curr().Lexeme, p.curr().Line)
-	}
+	return ast.NewAstNode(ast.STMT, ast.NewFuncDeclarationStmt(funcName, args, *nodeTbe, p.spanFrom(start))), nil
+}
 
-	p.consume(tokens.RIGHT_PAREN, *NewParserError(MISSING_RPAREN, p.curr().Lexeme, p.curr().Line))
+//	skibidi (...args) {
+//	  ...node
+//	}
+func (p *Parser) parseFuncExpr() (*ast.AstNode, *ParserError) {
+	start := p.curr()
 
-	nodeTbe, err := p.Parse()
-	if err != nil || nodeTbe == nil {
-		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
+	args, node, err := p.parseFuncSignatureAndBody()
+	if err != nil {
+		return nil, err
 	}
 
-	funcDeclarationStmt := ast.NewFuncDeclarationStmt(literalExpr.Value, args, *nodeTbe, p.curr().Line)
+	return ast.NewAstNode(ast.EXPR, ast.NewFuncExpr(args, *node, p.spanFrom(start))), nil
+}
 
-	currEnv := p.Runtime.CurrEnv()
-	funcNode, _ := currEnv.GetFunc(funcDeclarationStmt.Name)
-	if funcNode != nil {
-		return nil, NewParserError(IDENTIFIER_ALREADY_EXISTS, p.curr().Lexeme, p.curr().Line)
+// parses `(...args) { ...node }`, shared by function declarations and function expressions
+func (p *Parser) parseFuncSignatureAndBody() ([]ast.AstNode, *ast.AstNode, *ParserError) {
+	if err := p.consume(tokens.LEFT_PAREN, NewParserError(MISSING_LPAREN, p.curr().Lexeme, p.curr().Span)); err != nil {
+		return nil, nil, err
 	}
 
-	currEnv.SetFunc(funcDeclarationStmt.Name, funcDeclarationStmt.Node, funcDeclarationStmt.Args)
-
-	return ast.NewAstNode(ast.STMT, funcDeclarationStmt), nil
-}
-
-func (p *Parser) parseFuncCallStmt() (*ast.AstNode, *ParserError) {
-	funcName := p.curr().Lexeme
-	// checking whether next token is "(" or not is handled within the switch-case statement
-	p.advance()
+	lparen := p.curr()
 
 	var args []ast.AstNode
 
 	if p.peek().Type != tokens.RIGHT_PAREN {
 		// equivalent to do-while loop in java
 		for ok := true; ok; ok = p.matchAndAdvance(tokens.COMMA) {
-			node, err := p.Parse()
-
-			if err != nil || node == nil {
-				return nil, NewParserError(INVALID_EXPRESSION, p.curr().Lexeme, p.curr().Line)
+			if !p.matchAndAdvance(tokens.IDENTIFIER) {
+				return nil, nil, NewParserError(INVALID_EXPRESSION, p.peek().Lexeme, p.peek().Span)
 			}
 
-			args = append(args, *node)
+			args = append(args, *ast.NewAstNode(ast.EXPR, ast.NewLiteralExpr(tokens.IDENTIFIER, p.curr().Lexeme, p.curr().Span)))
 		}
 	}
 
-	var returnNode ast.AstNode
-
-	currEnvPtr := p.Runtime.CurrEnv()
-	if currEnvPtr != nil {
-		currEnv := *currEnvPtr
-
-		funcNode, ok := currEnv.Funcs[funcName]
-		if ok {
-			switch v := funcNode.Node.Value.(type) {
-			case ast.CreateBlockStmt:
-				for _, node := range v.Nodes {
-					_, ok := node.Value.(ast.ReturnStmt)
-					if ok {
-						returnNode = node
-					}
-				}
-			case ast.ReturnStmt:
-				returnNode = v.Node
-			}
-		}
+	if len(args) > 255 {
+		return nil, nil, NewParserError(TOO_MANY_ARGUMENTS, p.curr().Lexeme, p.curr().Span)
 	}
 
-	if len(args) >= 255 {
-		return nil, NewParserError("can't have more than 255 arguments", p.curr().Lexeme, p.curr().Line)
+	if err := p.consume(tokens.RIGHT_PAREN, p.unclosedError(MISSING_RPAREN, lparen)); err != nil {
+		return nil, nil, err
 	}
 
-	p.consume(tokens.RIGHT_PAREN, *NewParserError(MISSING_RPAREN, p.curr().Lexeme, p.curr().Line))
+	// a function body starts outside of any loop, even if the function is declared within one
+	loopDepth := p.loopDepth
+	p.loopDepth = 0
 
-	return ast.NewAstNode(ast.STMT, ast.NewFuncCallStmt(funcName, args, returnNode, p.curr().Line)), nil
+	node, err := p.parseBody()
+
+	p.loopDepth = loopDepth
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if node == nil {
+		return nil, nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
+	}
+
+	return args, node, nil
 }
 
 func (p *Parser) parseReturnStmt() (*ast.AstNode, *ParserError) {
+	start := p.curr()
+
 	node, err := p.Parse()
 	if err != nil {
 		return nil, err
 	}
 
 	if node == nil {
-		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
+		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
 	}
 
-	return ast.NewAstNode(ast.STMT, ast.NewReturnStmt(*node, p.curr().Line)), nil
+	return ast.NewAstNode(ast.STMT, ast.NewReturnStmt(*node, p.spanFrom(start))), nil
 }
 
-func (p *Parser) parseIncrementStmt() (*ast.AstNode, *ParserError) {
-	varName := p.curr().Lexeme
-	p.advance()
-
-	if utils.IsReservedKeyword(varName) {
-		return nil, NewParserError(INVALID_EXPRESSION, p.curr().Lexeme, p.curr().Line)
+func (p *Parser) parseBreakStmt() (*ast.AstNode, *ParserError) {
+	if p.loopDepth == 0 {
+		return nil, NewParserError(BREAK_OUTSIDE_LOOP, p.curr().Lexeme, p.curr().Span)
 	}
 
-	if varName == "" {
-		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
-	}
+	start := p.curr()
 
-	if !utils.IsAlphaOnly(varName) {
-		return nil, NewParserError(INVALID_EXPRESSION, p.curr().Lexeme, p.curr().Line)
+	if err := p.consume(tokens.SEMICOLON, NewParserError(MISSING_SEMICOLON, p.curr().Lexeme, p.curr().Span)); err != nil {
+		return nil, err
 	}
 
-	return ast.NewAstNode(ast.STMT, ast.NewIncrementStmt(varName, p.curr().Line)), nil
+	return ast.NewAstNode(ast.STMT, ast.NewBreakStmt(p.spanFrom(start))), nil
 }
 
-func (p *Parser) parseDecrementStmt() (*ast.AstNode, *ParserError) {
-	varName := p.curr().Lexeme
-	p.advance()
-
-	if utils.IsReservedKeyword(varName) {
-		return nil, NewParserError(INVALID_EXPRESSION, p.curr().Lexeme, p.curr().Line)
+func (p *Parser) parseContinueStmt() (*ast.AstNode, *ParserError) {
+	if p.loopDepth == 0 {
+		return nil, NewParserError(CONTINUE_OUTSIDE_LOOP, p.curr().Lexeme, p.curr().Span)
 	}
 
-	if varName == "" {
-		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
-	}
+	start := p.curr()
 
-	if !utils.IsAlphaOnly(varName) {
-		return nil, NewParserError(INVALID_EXPRESSION, p.curr().Lexeme, p.curr().Line)
+	if err := p.consume(tokens.SEMICOLON, NewParserError(MISSING_SEMICOLON, p.curr().Lexeme, p.curr().Span)); err != nil {
+		return nil, err
 	}
 
-	return ast.NewAstNode(ast.STMT, ast.NewDecrementStmt(varName, p.curr().Line)), nil
+	return ast.NewAstNode(ast.STMT, ast.NewContinueStmt(p.spanFrom(start))), nil
 }
 
 //	chillin ((init); (condition); (update)) {
@@ -366,82 +369,88 @@ func (p *Parser) parseDecrementStmt() (*ast.AstNode, *ParserError) {
 //
 // `init`	 -> variable declaration statement
 // `condition` -> binary expression with comparision operator
-// `update` -> variable re-declaration statement
+// `update` -> variable re-assignment, compound assignment, `++` or `--`
 func (p *Parser) parseForStmt() (*ast.AstNode, *ParserError) {
-	p.consume(tokens.LEFT_PAREN, *NewParserError(MISSING_LPAREN, p.curr().Lexeme, p.curr().Line))
+	start := p.curr()
+
+	if err := p.consume(tokens.LEFT_PAREN, NewParserError(MISSING_LPAREN, p.curr().Lexeme, p.curr().Span)); err != nil {
+		return nil, err
+	}
+
+	lparen := p.curr()
 
 	initNode, err := p.Parse()
-	if err != nil || initNode == nil {
-		return nil, NewParserError(INVALID_EXPRESSION, p.curr().Lexeme, p.curr().Line)
+	if err != nil {
+		return nil, err
+	}
+
+	if initNode == nil {
+		return nil, NewParserError(INVALID_EXPRESSION, p.curr().Lexeme, p.curr().Span)
 	}
 
 	initStmt, ok := initNode.Value.(ast.Stmt)
 	if !ok {
-		return nil, NewParserError(STATEMENT_EXPECTED, p.curr().Lexeme, p.curr().Line)
+		return nil, NewParserError(STATEMENT_EXPECTED, p.curr().Lexeme, p.curr().Span)
 	}
 
 	if _, ok := initStmt.(ast.VarAssignStmt); !ok {
-		return nil, NewParserError(fmt.Sprintf(INVALID_STATEMENT_TEMPLATE, "variable assignment"), p.curr().Lexeme, p.curr().Line)
+		return nil, NewParserError(INVALID_STATEMENT_TEMPLATE.With("variable assignment"), p.curr().Lexeme, p.curr().Span)
 	}
 
 	// `parseVarAssignStmt` checks if `;` is present, so it isn't required to check it over again
-	// p.consume(tokens.SEMICOLON, *NewParserError(MISSING_SEMICOLON, p.curr().Lexeme, p.curr().Line))
+	// p.consume(tokens.SEMICOLON, NewParserError(MISSING_SEMICOLON, p.curr().Lexeme, p.curr().Span))
 
 	conditionNode, err := p.Parse()
+	if err != nil {
+		return nil, err
+	}
 
-	if err != nil || conditionNode == nil {
-		return nil, NewParserError(INVALID_EXPRESSION, p.curr().Lexeme, p.curr().Line)
+	if conditionNode == nil {
+		return nil, NewParserError(INVALID_EXPRESSION, p.curr().Lexeme, p.curr().Span)
 	}
 
 	conditionExpr, ok := conditionNode.Value.(ast.Expr)
 	if !ok {
-		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
+		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
 	}
 
 	binaryExpr, ok := conditionExpr.(ast.BinaryExpr)
 	if !ok {
-		return nil, NewParserError(fmt.Sprintf(INVALID_STATEMENT_TEMPLATE, "variable assignment"), p.curr().Lexeme, p.curr().Line)
+		return nil, NewParserError(INVALID_STATEMENT_TEMPLATE.With("variable assignment"), p.curr().Lexeme, p.curr().Span)
 	}
 
 	if !slices.Contains([]tokens.TokenType{tokens.LESS, tokens.LESS_EQUAL, tokens.GREATER, tokens.GREATER_EQUAL, tokens.EQUAL_EQUAL, tokens.BANG_EQUAL}, binaryExpr.Operator) {
-		return nil, NewParserError(fmt.Sprintf(INVALID_OPERATOR_TEMPLATE, binaryExpr.Operator.Literal()), p.curr().Lexeme, p.curr().Line)
+		return nil, NewParserError(INVALID_OPERATOR_TEMPLATE.With(binaryExpr.Operator.Literal()), p.curr().Lexeme, p.curr().Span)
 	}
 
 	// parse functions for expressions don't check if they end in a `;`
-	p.consume(tokens.SEMICOLON, *NewParserError(MISSING_SEMICOLON, p.curr().Lexeme, p.curr().Line))
+	if err := p.consume(tokens.SEMICOLON, NewParserError(MISSING_SEMICOLON, p.curr().Lexeme, p.curr().Span)); err != nil {
+		return nil, err
+	}
 
 	updateNode, err := p.Parse()
 	if err != nil {
 		return nil, err
 	}
 
-	updateStmt, ok := updateNode.Value.(ast.Stmt)
-	if !ok {
-		return nil, NewParserError(STATEMENT_EXPECTED, p.curr().Lexeme, p.curr().Line)
+	if updateNode == nil {
+		return nil, NewParserError(STATEMENT_EXPECTED, p.curr().Lexeme, p.curr().Span)
 	}
 
-	_, isVarReassignStmt := updateStmt.(ast.VarReassignStmt)
-	_, isIncrementStmt := updateStmt.(ast.IncrementStmt)
-	_, isDecrementStmt := updateStmt.(ast.DecrementStmt)
-
-	if !(isVarReassignStmt || isIncrementStmt || isDecrementStmt) {
-		return nil, NewParserError(fmt.Sprintf(INVALID_STATEMENT_TEMPLATE, "variable re-assignment"), p.curr().Lexeme, p.curr().Line)
+	switch updateNode.Value.(type) {
+	case ast.AssignExpr, ast.CompoundAssignExpr, ast.IncrementExpr:
+	default:
+		return nil, NewParserError(INVALID_STATEMENT_TEMPLATE.With("variable re-assignment"), p.curr().Lexeme, p.curr().Span)
 	}
 
-	p.consume(tokens.RIGHT_PAREN, *NewParserError(MISSING_RPAREN, p.curr().Lexeme, p.curr().Line))
+	if err := p.consume(tokens.RIGHT_PAREN, p.unclosedError(MISSING_RPAREN, lparen)); err != nil {
+		return nil, err
+	}
 
-	node, err := p.Parse()
+	node, err := p.parseLoopBody()
 	if err != nil {
 		return nil, err
 	}
 
-	return ast.NewAstNode(ast.STMT, ast.NewForStmt(*node, *initNode, *conditionNode, *updateNode, p.curr().Line)), nil
-}
-
-// vibeCheck()
-func (p *Parser) parseNativeClockFnStmt() (*ast.AstNode, *ParserError) {
-	p.consume(tokens.LEFT_PAREN, *NewParserError(MISSING_LPAREN, p.curr().Lexeme, p.curr().Line))
-	p.consume(tokens.RIGHT_PAREN, *NewParserError(MISSING_RPAREN, p.curr().Lexeme, p.curr().Line))
-
-	return ast.NewAstNode(ast.STMT, ast.NewNativeClockFnStmt(p.curr().Line)), nil
+	return ast.NewAstNode(ast.STMT, ast.NewForStmt(*node, *initNode, *conditionNode, *updateNode, p.spanFrom(start))), nil
 }
@@ -2,35 +2,38 @@ package parser
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/0xmukesh/interpreter/internal/ast"
-	"github.com/0xmukesh/interpreter/internal/runtime"
+	"github.com/0xmukesh/interpreter/internal/diagnostics"
 	"github.com/0xmukesh/interpreter/internal/tokens"
 	"github.com/0xmukesh/interpreter/internal/utils"
 )
 
 type Parser struct {
-	Tokens  []tokens.Token
-	Runtime *runtime.Runtime
-	Idx     int
+	Tokens []tokens.Token
+	Idx    int
+	// number of loops enclosing the current token within the current function, `yeet` and `skrrt`
+	// are only allowed if it isn't 0
+	loopDepth int
+	// errors parsing recovered from
+	errors ParserErrors
 }
 
-func NewParser(tokens []tokens.Token, runtime *runtime.Runtime) *Parser {
+func NewParser(tokens []tokens.Token) *Parser {
 	return &Parser{
-		Tokens:  tokens,
-		Runtime: runtime,
-		Idx:     0,
+		Tokens: tokens,
+		Idx:    0,
 	}
 }
 
 func (p *Parser) GetCurrLine() int {
-	return p.curr().Line
+	return p.curr().GetLine()
 }
 
 func (p *Parser) curr() tokens.Token {
 	if p.Idx-1 < 0 {
-		return *tokens.NewToken(tokens.IGNORE, "", "", 1)
+		start := tokens.Position{Line: 1, Column: 1}
+		return *tokens.NewToken(tokens.IGNORE, "", "", tokens.Span{Start: start, End: start})
 	}
 	return p.Tokens[p.Idx-1]
 }
@@ -61,7 +64,12 @@ func (p *Parser) isAtEnd() bool {
 }
 
 func (p *Parser) isSameLine() bool {
-	return p.curr().Line == p.peek().Line
+	return p.curr().GetLine() == p.peek().GetLine()
+}
+
+// span from the start of `start` to the end of the current token
+func (p *Parser) spanFrom(start tokens.Token) tokens.Span {
+	return start.Span.To(p.curr().Span)
 }
 
 func (p *Parser) check(expected tokens.TokenType) bool {
@@ -85,19 +93,55 @@ func (p *Parser) matchAndAdvance(expectedTypes ...tokens.TokenType) bool {
 	return false
 }
 
-func (p *Parser) consume(expected tokens.TokenType, err ParserError) {
+// reports whether the "{" which was just consumed opens a map literal rather than a block. it is a
+// map if it is empty or if a ":" shows up before the first statement ends
+func (p *Parser) isMapLiteral() bool {
+	depth := 0
+
+	for i := p.Idx; i < len(p.Tokens); i++ {
+		switch p.Tokens[i].Type {
+		case tokens.LEFT_PAREN, tokens.LEFT_BRACKET, tokens.LEFT_BRACE:
+			depth++
+		case tokens.RIGHT_PAREN, tokens.RIGHT_BRACKET:
+			depth--
+		case tokens.RIGHT_BRACE:
+			if depth == 0 {
+				return i == p.Idx
+			}
+			depth--
+		case tokens.SEMICOLON:
+			if depth == 0 {
+				return false
+			}
+		case tokens.COLON:
+			if depth == 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// advances past the next token if it is of the expected type, otherwise returns `err`
+func (p *Parser) consume(expected tokens.TokenType, err *ParserError) *ParserError {
 	if p.check(expected) {
 		p.advance()
-		return
+		return nil
 	}
 
-	utils.EPrint(fmt.Sprintf("%s\n", err.Error()))
+	return err
+}
+
+// error for a missing closing delimiter, pointing back at the token which opened it
+func (p *Parser) unclosedError(msg diagnostics.Message, opening tokens.Token) *ParserError {
+	return NewParserError(msg, p.curr().Lexeme, p.curr().Span).WithNote(fmt.Sprintf(OPENED_HERE_NOTE_TEMPLATE, opening.Lexeme), &opening.Span)
 }
 
 func (p *Parser) extractExpr(node ast.AstNode) (ast.Expr, *ParserError) {
 	expr := node.ExtractExpr()
 	if expr == nil {
-		err := NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
+		err := NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
 		return nil, err
 	}
 
@@ -110,108 +154,152 @@ func (p *Parser) Parse() (*ast.AstNode, *ParserError) {
 	}
 
 	p.Idx++
-	return p.equalityRule()
+	return p.expressionRule(LOWEST)
 }
 
-func (p *Parser) BuildAst() (ast.Ast, *ParserError) {
+// BuildAst parses the whole program. a statement which fails to parse is skipped so the following
+// ones are still checked, and the errors of all of them are returned together
+func (p *Parser) BuildAst() (ast.Ast, ParserErrors) {
 	var ast ast.Ast
 
-	for {
+	for !p.isAtEnd() {
 		node, err := p.Parse()
 		if err != nil {
-			return nil, err
+			p.recover(err)
+			continue
 		}
 
 		if node != nil {
 			ast = append(ast, *node)
 		}
+	}
 
-		if p.isAtEnd() {
-			return ast, nil
-		}
+	if len(p.errors) > 0 {
+		return nil, p.errors
 	}
+
+	return ast, nil
 }
 
-func (p *Parser) binaryRuleBuilder(selfRule func() (*ast.AstNode, *ParserError), nextRule func() (*ast.AstNode, *ParserError), expectedTokens ...tokens.TokenType) (*ast.AstNode, *ParserError) {
-	leftNode, err := nextRule()
-	if err != nil {
-		return nil, err
-	}
+// records `err` and skips to the start of the next statement
+func (p *Parser) recover(err *ParserError) {
+	p.errors = append(p.errors, err)
+	p.synchronize()
+}
 
-	if leftNode != nil {
-		token := p.peek()
+// skips tokens until the next statement starts, which is after a ";" or at a statement keyword.
+// blocks opened while skipping are skipped as a whole, and the "}" closing the block the error is
+// in is left for the block to consume
+func (p *Parser) synchronize() {
+	depth := 0
+
+	for !p.isAtEnd() {
+		switch p.curr().Type {
+		case tokens.LEFT_BRACE:
+			depth++
+		case tokens.RIGHT_BRACE:
+			if depth > 0 {
+				depth--
+			}
+		case tokens.SEMICOLON:
+			if depth == 0 {
+				return
+			}
+		}
 
-		if p.matchAndAdvance(expectedTokens...) {
-			if !p.isSameLine() {
-				return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
+		if depth == 0 {
+			switch p.peek().Type {
+			case tokens.RIGHT_BRACE, tokens.VAR, tokens.IF, tokens.WHILE, tokens.FOR, tokens.FUNC, tokens.RETURN, tokens.PRINT:
+				return
 			}
+		}
 
-			p.advance()
+		p.advance()
+	}
+}
 
-			rightNode, err := selfRule()
-			if err != nil {
-				return nil, err
-			}
+// parses an expression via precedence climbing. only binary operators which bind tighter than
+// `minPrecedence` are folded into the expression, and since the right operand is parsed with the
+// operator's own precedence, operators of the same precedence associate to the left
+func (p *Parser) expressionRule(minPrecedence Precedence) (*ast.AstNode, *ParserError) {
+	leftNode, err := p.unaryRule()
+	if err != nil || leftNode == nil {
+		return leftNode, err
+	}
+
+	for !p.isAtEnd() {
+		// statements can't be the left operand of a binary operator
+		if _, isStmt := leftNode.Value.(ast.Stmt); isStmt {
+			break
+		}
 
-			if rightNode == nil {
-				return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
-			} else {
-				leftExpr, err := p.extractExpr(*leftNode)
-				if err != nil {
-					return nil, err
-				}
+		operator := p.peek()
 
-				rightExpr, err := p.extractExpr(*rightNode)
-				if err != nil {
-					return nil, err
-				}
+		precedence, isBinaryOperator := binaryPrecedences[operator.Type]
+		if !isBinaryOperator || precedence <= minPrecedence {
+			break
+		}
 
-				if rightExpr.ParseExpr() == "null" {
-					return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
-				}
+		p.advance()
 
-				leftNode = ast.NewAstNode(ast.EXPR, ast.NewBinaryExpr(leftExpr, token.Type, rightExpr, p.curr().Line))
-			}
+		if p.isAtEnd() || !p.isSameLine() {
+			return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
 		}
-	}
 
-	return leftNode, nil
-}
+		p.advance()
 
-func (p *Parser) equalityRule() (*ast.AstNode, *ParserError) {
-	return p.binaryRuleBuilder(p.equalityRule, p.comparisonRule, tokens.EQUAL_EQUAL, tokens.BANG_EQUAL)
-}
+		rightNode, err := p.expressionRule(precedence)
+		if err != nil {
+			return nil, err
+		}
 
-func (p *Parser) comparisonRule() (*ast.AstNode, *ParserError) {
-	return p.binaryRuleBuilder(p.comparisonRule, p.subtractionRule, tokens.LESS, tokens.LESS_EQUAL, tokens.GREATER, tokens.GREATER_EQUAL)
-}
+		if rightNode == nil {
+			return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
+		}
 
-func (p *Parser) subtractionRule() (*ast.AstNode, *ParserError) {
-	return p.binaryRuleBuilder(p.subtractionRule, p.additionRule, tokens.MINUS, tokens.MINUS_MINUS)
-}
+		leftExpr, err := p.extractExpr(*leftNode)
+		if err != nil {
+			return nil, err
+		}
 
-func (p *Parser) additionRule() (*ast.AstNode, *ParserError) {
-	return p.binaryRuleBuilder(p.additionRule, p.multiplicationRule, tokens.PLUS, tokens.PLUS_PLUS)
-}
+		rightExpr, err := p.extractExpr(*rightNode)
+		if err != nil {
+			return nil, err
+		}
 
-func (p *Parser) multiplicationRule() (*ast.AstNode, *ParserError) {
-	return p.binaryRuleBuilder(p.multiplicationRule, p.divisionRule, tokens.STAR)
-}
+		if operator.Type == tokens.AND || operator.Type == tokens.OR {
+			leftNode = ast.NewAstNode(ast.EXPR, ast.NewLogicalExpr(leftExpr, operator.Type, rightExpr, leftExpr.GetSpan().To(rightExpr.GetSpan())))
+			continue
+		}
 
-func (p *Parser) divisionRule() (*ast.AstNode, *ParserError) {
-	return p.binaryRuleBuilder(p.divisionRule, p.moduloRule, tokens.SLASH)
-}
+		leftNode = ast.NewAstNode(ast.EXPR, ast.NewBinaryExpr(leftExpr, operator.Type, rightExpr, leftExpr.GetSpan().To(rightExpr.GetSpan())))
+	}
+
+	// the whole left side is the target, so `a + b += 1` is an error rather than `a + (b += 1)`
+	if operator, isCompound := compoundOperators[p.peek().Type]; isCompound && minPrecedence == LOWEST && !p.isAtEnd() {
+		return p.parseCompoundAssignExpr(*leftNode, operator)
+	}
 
-func (p *Parser) moduloRule() (*ast.AstNode, *ParserError) {
-	return p.binaryRuleBuilder(p.moduloRule, p.unaryRule, tokens.MODULO)
+	if p.peek().Type == tokens.EQUAL && minPrecedence == LOWEST && !p.isAtEnd() {
+		return p.parseAssignExpr(*leftNode)
+	}
+
+	return leftNode, nil
 }
 
+// prefix operators bind tighter than every binary operator, so `-a + b` is `(-a) + b`
 func (p *Parser) unaryRule() (*ast.AstNode, *ParserError) {
-	expectedOperators := []tokens.TokenType{tokens.BANG, tokens.MINUS}
+	expectedOperators := []tokens.TokenType{tokens.BANG, tokens.MINUS, tokens.BIT_NOT}
 	operator := p.curr()
 
 	if utils.HasValueArray(expectedOperators, operator.Type) {
-		node, err := p.Parse()
+		if p.isAtEnd() {
+			return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
+		}
+
+		p.advance()
+
+		node, err := p.unaryRule()
 		if err != nil {
 			return nil, err
 		}
@@ -222,63 +310,128 @@ func (p *Parser) unaryRule() (*ast.AstNode, *ParserError) {
 				return nil, err
 			}
 
-			return ast.NewAstNode(ast.EXPR, ast.NewUnaryExpr(operator.Type, expr, p.curr().Line)), nil
+			return ast.NewAstNode(ast.EXPR, ast.NewUnaryExpr(operator.Type, expr, operator.Span.To(expr.GetSpan()))), nil
 		}
 
-		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
+		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
 	}
 
-	return p.logicalRule()
+	return p.powerRule()
 }
 
-func (p *Parser) logicalRule() (*ast.AstNode, *ParserError) {
-	leftNode, err := p.primaryRule()
+// ++(name) and --(name). they bind looser than calls and indexing, so `++xs[0]` is an error rather
+// than `(++xs)[0]`
+func (p *Parser) incrementRule() (*ast.AstNode, *ParserError) {
+	operator := p.curr()
+
+	if operator.Type != tokens.PLUS_PLUS && operator.Type != tokens.MINUS_MINUS {
+		return p.postfixRule()
+	}
+
+	if p.isAtEnd() || !p.isSameLine() {
+		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
+	}
+
+	p.advance()
+
+	node, err := p.postfixRule()
 	if err != nil {
 		return nil, err
 	}
 
-	if leftNode != nil {
-		token := p.peek()
+	if node == nil {
+		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
+	}
 
-		if p.matchAndAdvance(tokens.AND, tokens.OR) {
-			if !p.isSameLine() {
-				return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
-			}
+	target, err := p.assignmentTarget(*node, operator)
+	if err != nil {
+		return nil, err
+	}
 
-			p.advance()
+	return ast.NewAstNode(ast.EXPR, ast.NewIncrementExpr(target.Value, operator.Type, true, operator.Span.To(target.Span))), nil
+}
 
-			rightNode, err := p.logicalRule()
-			if err != nil {
-				return nil, err
-			}
+// (base) ** (exponent). it's right associative and its exponent can have a prefix operator, so
+// 2 ** -1 ** 2 is 2 ** (-(1 ** 2)), while -2 ** 2 is -(2 ** 2)
+func (p *Parser) powerRule() (*ast.AstNode, *ParserError) {
+	baseNode, err := p.incrementRule()
+	if err != nil || baseNode == nil {
+		return baseNode, err
+	}
+
+	if _, isStmt := baseNode.Value.(ast.Stmt); isStmt || p.peek().Type != tokens.STAR_STAR || p.isAtEnd() {
+		return baseNode, nil
+	}
 
-			if rightNode == nil {
-				return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
-			} else {
-				leftExpr, err := p.extractExpr(*leftNode)
-				if err != nil {
-					return nil, err
-				}
+	p.advance()
 
-				rightExpr, err := p.extractExpr(*rightNode)
-				if err != nil {
-					return nil, err
-				}
+	if p.isAtEnd() || !p.isSameLine() {
+		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
+	}
 
-				if rightExpr.ParseExpr() == "null" {
-					return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Line)
-				}
+	p.advance()
 
-				leftNode = ast.NewAstNode(ast.EXPR, ast.NewLogicalExpr(leftExpr, token.Type, rightExpr, p.curr().Line))
-			}
+	exponentNode, err := p.unaryRule()
+	if err != nil {
+		return nil, err
+	}
+
+	if exponentNode == nil {
+		return nil, NewParserError(EXPRESSION_EXPECTED, p.curr().Lexeme, p.curr().Span)
+	}
+
+	base, err := p.extractExpr(*baseNode)
+	if err != nil {
+		return nil, err
+	}
+
+	exponent, err := p.extractExpr(*exponentNode)
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewAstNode(ast.EXPR, ast.NewBinaryExpr(base, tokens.STAR_STAR, exponent, base.GetSpan().To(exponent.GetSpan()))), nil
+}
+
+// wraps the primary node in function calls and index accesses as long as it is followed by "(" or "["
+func (p *Parser) postfixRule() (*ast.AstNode, *ParserError) {
+	node, err := p.primaryRule()
+	if err != nil || node == nil {
+		return node, err
+	}
+
+	for isPostfixable(*node) {
+		switch p.peek().Type {
+		case tokens.LEFT_PAREN:
+			node, err = p.parseCallExpr(*node)
+		case tokens.LEFT_BRACKET:
+			node, err = p.parseIndexExpr(*node)
+		default:
+			return p.parsePostfixIncrementExpr(*node)
+		}
+
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	return leftNode, nil
+	return p.parsePostfixIncrementExpr(*node)
+}
+
+// reports whether the node can syntactically be called or indexed
+func isPostfixable(node ast.AstNode) bool {
+	switch v := node.Value.(type) {
+	case ast.LiteralExpr:
+		return v.TokenType == tokens.IDENTIFIER || v.TokenType == tokens.STRING
+	case ast.GroupingExpr, ast.FuncExpr, ast.CallExpr, ast.ListExpr, ast.MapExpr, ast.IndexExpr, ast.InterpolatedStrExpr:
+		return true
+	default:
+		return false
+	}
 }
 
 func (p *Parser) primaryRule() (*ast.AstNode, *ParserError) {
-	canIgnore := []tokens.TokenType{tokens.EOF, tokens.ILLEGAL, tokens.IGNORE}
+	canIgnore := []tokens.TokenType{tokens.EOF, tokens.ILLEGAL, tokens.IGNORE, tokens.SEMICOLON, tokens.STRING_MIDDLE, tokens.STRING_END}
 
 	if utils.HasValueArray(canIgnore, p.curr().Type) {
 		return nil, nil
@@ -288,7 +441,15 @@ func (p *Parser) primaryRule() (*ast.AstNode, *ParserError) {
 	case tokens.LEFT_PAREN:
 		return p.parseGroupingExpr()
 	case tokens.LEFT_BRACE:
+		if p.isMapLiteral() {
+			return p.parseMapExpr()
+		}
+
 		return p.parseCreateBlockStmt()
+	case tokens.LEFT_BRACKET:
+		return p.parseListExpr()
+	case tokens.STRING_START:
+		return p.parseInterpolatedStrExpr()
 	case tokens.PRINT:
 		return p.parsePrintStmt()
 	case tokens.VAR:
@@ -296,9 +457,9 @@ func (p *Parser) primaryRule() (*ast.AstNode, *ParserError) {
 	case tokens.IF:
 		return p.parseIfStmt()
 	case tokens.ELSE_IF:
-		return nil, NewParserError(MISSING_IF_BRANCH, p.curr().Lexeme, p.curr().Line)
+		return nil, NewParserError(MISSING_IF_BRANCH, p.curr().Lexeme, p.curr().Span)
 	case tokens.ELSE:
-		return nil, NewParserError(MISSING_IF_BRANCH, p.curr().Lexeme, p.curr().Line)
+		return nil, NewParserError(MISSING_IF_BRANCH, p.curr().Lexeme, p.curr().Span)
 	case tokens.WHILE:
 		return p.parseWhileStmt()
 	case tokens.FOR:
@@ -307,33 +468,19 @@ func (p *Parser) primaryRule() (*ast.AstNode, *ParserError) {
 		return p.parseFuncDeclarationStmt()
 	case tokens.RETURN:
 		return p.parseReturnStmt()
-	case tokens.IDENTIFIER:
-		if !p.prev().Type.IsReserved() {
-			switch p.peek().Type {
-			case tokens.EQUAL:
-				return p.parseVarReassignStmt()
-			case tokens.LEFT_PAREN:
-				if utils.IsNativeFunc(p.curr().Lexeme) {
-					switch p.curr().Lexeme {
-					case runtime.VibeCheck:
-						return p.parseNativeClockFnStmt()
-					}
-				} else {
-					return p.parseFuncCallStmt()
-				}
-			case tokens.PLUS_PLUS:
-				return p.parseIncrementStmt()
-			case tokens.MINUS_MINUS:
-				return p.parseDecrementStmt()
-			}
-		}
+	case tokens.BREAK:
+		return p.parseBreakStmt()
+	case tokens.CONTINUE:
+		return p.parseContinueStmt()
 	}
 
 	literal := p.curr().Lexeme
 
-	if p.curr().Type == tokens.STRING {
-		literal = strings.TrimSuffix(strings.TrimPrefix(p.curr().Lexeme, `"`), `"`)
+	// the lexer already turned the string's escape sequences into the characters they stand for, and
+	// numbers into base 10 without underscores
+	if p.curr().Type == tokens.STRING || p.curr().Type == tokens.NUMBER {
+		literal = p.curr().Literal
 	}
 
-	return ast.NewAstNode(ast.EXPR, ast.NewLiteralExpr(p.curr().Type, literal, p.curr().Line)), nil
+	return ast.NewAstNode(ast.EXPR, ast.NewLiteralExpr(p.curr().Type, literal, p.curr().Span)), nil
 }
@@ -1,47 +1,109 @@
 package parser
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
 
-const (
-	EXPRESSION_EXPECTED          = "yo, where's the vibe? i was expecting an expression over here"
-	EXPRESSION_EXPECTED_TEMPLATE = "yo, where's the vibe? i was expecting an %s expression over here"
+	"github.com/0xmukesh/interpreter/internal/diagnostics"
+	"github.com/0xmukesh/interpreter/internal/tokens"
+)
+
+var (
+	EXPRESSION_EXPECTED          = diagnostics.Message{Code: "P001", Text: "yo, where's the vibe? i was expecting an expression over here"}
+	EXPRESSION_EXPECTED_TEMPLATE = diagnostics.Message{Code: "P002", Text: "yo, where's the vibe? i was expecting an %s expression over here"}
+
+	STATEMENT_EXPECTED = diagnostics.Message{Code: "P003", Text: "yo, where's the vibe? i was expecting a statement over here"}
 
-	STATEMENT_EXPECTED = "yo, where's the vibe? i was expecting a statement over here"
+	EXPRESSION_AFTER_ASSIGNMENT_EXPECTED = diagnostics.Message{Code: "P004", Text: "vibe check failed, blud misunderstood the assignment. i was expecting an expression after assignment"}
+	VARIABLE_NAME_EXPECTED               = diagnostics.Message{Code: "P005", Text: "yo, where's the vibe? i was expecting a variable name over here"}
 
-	EXPRESSION_AFTER_ASSIGNMENT_EXPECTED = "vibe check failed, blud misunderstood the assignment. i was expecting an expression after assignment"
-	VARIABLE_NAME_EXPECTED               = "yo, where's the vibe? i was expecting a variable name over here"
+	MISSING_SEMICOLON = diagnostics.Message{Code: "P006", Text: "nahh, you left me hanging. where's ';' at?"}
+	MISSING_LPAREN    = diagnostics.Message{Code: "P007", Text: "bruh, where's the '('? you can't just skip it like that"}
+	MISSING_RPAREN    = diagnostics.Message{Code: "P008", Text: "nahh, you left me hanging. where's ')' at?"}
+	MISSING_RBRACE    = diagnostics.Message{Code: "P009", Text: "nahh, you left me hanging. where's '}' at?"}
+	MISSING_RBRACKET  = diagnostics.Message{Code: "P010", Text: "nahh, you left me hanging. where's ']' at?"}
+	MISSING_COLON     = diagnostics.Message{Code: "P011", Text: "nahh, you left me hanging. where's ':' at?"}
+	MISSING_IF_BRANCH = diagnostics.Message{Code: "P012", Text: "bruh, where's the 'if' branch? you can't just skip it like that"}
 
-	MISSING_SEMICOLON = "nahh, you left me hanging. where's ';' at?"
-	MISSING_LPAREN    = "bruh, where's the '('? you can't just skip it like that"
-	MISSING_RPAREN    = "nahh, you left me hanging. where's ')' at?"
-	MISSING_RBRACE    = "nahh, you left me hanging. where's '}' at?"
-	MISSING_IF_BRANCH = "bruh, where's the 'if' branch? you can't just skip it like that"
+	BREAK_OUTSIDE_LOOP    = diagnostics.Message{Code: "P013", Text: "bruh, you can't yeet outta nothing. 'yeet' only works inside a loop"}
+	CONTINUE_OUTSIDE_LOOP = diagnostics.Message{Code: "P014", Text: "bruh, where you skrrting to? 'skrrt' only works inside a loop"}
 
-	INVALID_TOKEN_TYPE_TEMPLATE = "ay, that token isn't allowed. expected %s token"
+	INVALID_TOKEN_TYPE_TEMPLATE = diagnostics.Message{Code: "P015", Text: "ay, that token isn't allowed. expected %s token"}
 
-	INVALID_VARIABLE_NAME      = "who tf even allowed you to name this variable?"
-	INVALID_EXPRESSION         = "invalid expression? wow, didn't know we were coding in clown mode today"
-	INVALID_STATEMENT          = "invalid statement? wow, didn't know we were coding in clown mode today"
-	INVALID_STATEMENT_TEMPLATE = "invalid statement? wow, didn't know we were coding in clown mode today. expected %s statement"
-	INVALID_OPERATOR_TEMPLATE  = "this operator ain't it, chief. expected a comparision operator but got %s"
+	INVALID_VARIABLE_NAME      = diagnostics.Message{Code: "P016", Text: "who tf even allowed you to name this variable?"}
+	INVALID_EXPRESSION         = diagnostics.Message{Code: "P017", Text: "invalid expression? wow, didn't know we were coding in clown mode today"}
+	INVALID_STATEMENT          = diagnostics.Message{Code: "P018", Text: "invalid statement? wow, didn't know we were coding in clown mode today"}
+	INVALID_STATEMENT_TEMPLATE = diagnostics.Message{Code: "P019", Text: "invalid statement? wow, didn't know we were coding in clown mode today. expected %s statement"}
+	INVALID_OPERATOR_TEMPLATE  = diagnostics.Message{Code: "P020", Text: "this operator ain't it, chief. expected a comparision operator but got %s"}
 
-	IDENTIFIER_ALREADY_EXISTS = "nah, the sequel ain't happening for this identifier"
+	INVALID_ASSIGNMENT_TARGET_TEMPLATE = diagnostics.Message{Code: "P022", Text: "that ain't a variable, chief. only variables can be updated with '%s'"}
+
+	TOO_MANY_ARGUMENTS = diagnostics.Message{Code: "P021", Text: "can't have more than 255 arguments"}
 )
 
+const OPENED_HERE_NOTE_TEMPLATE = "the '%s' was opened here"
+
 type ParserError struct {
+	Code    string
 	Message string
 	At      string
-	Line    int
+	Span    tokens.Span
+	Notes   []diagnostics.Note
+	// name of the file being parsed, it's empty when the source doesn't come from a file
+	File string
 }
 
-func NewParserError(msg string, at string, line int) *ParserError {
+func NewParserError(msg diagnostics.Message, at string, span tokens.Span) *ParserError {
 	return &ParserError{
-		Message: msg,
+		Code:    msg.Code,
+		Message: msg.Text,
 		At:      at,
-		Line:    line,
+		Span:    span,
 	}
 }
 
+// ParserErrors are all the errors found while parsing a program, in the order they were found
+type ParserErrors []*ParserError
+
+func (e ParserErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+func (e ParserErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+
+	return errs
+}
+
+// adds a note to the error, `span` is nil if the note doesn't point at the source
+func (e *ParserError) WithNote(msg string, span *tokens.Span) *ParserError {
+	e.Notes = append(e.Notes, diagnostics.Note{Message: msg, Span: span})
+	return e
+}
+
+func (e ParserError) label() string {
+	return fmt.Sprintf("hell naw, im done with you. you caused a parser error at '%s'", e.At)
+}
+
 func (e ParserError) Error() string {
-	return fmt.Sprintf("[line %d] hell naw, im done with you. you caused a parser error at '%s': %s", e.Line, e.At, e.Message)
+	return fmt.Sprintf("[%s] %s: %s", e.Span.Start.Format(e.File), e.label(), e.Message)
+}
+
+func (e ParserError) Diagnostic() diagnostics.Diagnostic {
+	return diagnostics.Diagnostic{
+		Severity: diagnostics.ERROR,
+		Code:     e.Code,
+		Message:  e.Message,
+		Label:    e.label(),
+		Span:     e.Span,
+		Notes:    e.Notes,
+	}
 }
@@ -0,0 +1,485 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/0xmukesh/interpreter/internal/ast"
+	"github.com/0xmukesh/interpreter/internal/diagnostics"
+	"github.com/0xmukesh/interpreter/internal/helpers"
+	"github.com/0xmukesh/interpreter/internal/lexer"
+)
+
+func parseExprString(t *testing.T, src string) (string, *ParserError) {
+	t.Helper()
+
+	tkns, lexErr := helpers.ProcessTokens(lexer.NewLexer([]byte(src)))
+	if lexErr != nil {
+		t.Fatalf("lexing %q: %s", src, lexErr.Error())
+	}
+
+	p := NewParser(tkns)
+
+	node, err := p.Parse()
+	if err != nil {
+		return "", err
+	}
+
+	// errors within blocks are recovered from and only kept by the parser
+	if len(p.errors) > 0 {
+		return "", p.errors[0]
+	}
+
+	if !p.isAtEnd() {
+		t.Fatalf("parsing %q: stopped at %q", src, p.peek().Lexeme)
+	}
+
+	return node.ExtractExpr().ParseExpr(), nil
+}
+
+func TestExprPrecedenceAndAssociativity(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{"1 + 2 * 3", "(+ 1 (* 2 3))"},
+		{"1 * 2 + 3", "(+ (* 1 2) 3)"},
+		{"10 - 3 - 2", "(- (- 10 3) 2)"},
+		{"1 - 2 + 3", "(+ (- 1 2) 3)"},
+		{"1 + 2 - 3", "(- (+ 1 2) 3)"},
+		{"8 / 4 / 2", "(/ (/ 8 4) 2)"},
+		{"8 / 4 * 2", "(* (/ 8 4) 2)"},
+		{"7 % 3 * 2", "(* (% 7 3) 2)"},
+		{"7 * 3 % 2", "(% (* 7 3) 2)"},
+		{"10 - 6 / 2", "(- 10 (/ 6 2))"},
+		{"1 + 2 < 4", "(< (+ 1 2) 4)"},
+		{"a < b == c > d", "(== (< a b) (> c d))"},
+		{"a == b != c", "(!= (== a b) c)"},
+		{"age >= 13 && age < 18", "(&& (>= age 13) (< age 18))"},
+		{"a && b || c", "(|| (&& a b) c)"},
+		{"a || b && c", "(|| a (&& b c))"},
+		{"a || b || c", "(|| (|| a b) c)"},
+		{"a && b && c", "(&& (&& a b) c)"},
+		{"i % 3 == 0 && i % 5 == 0", "(&& (== (% i 3) 0) (== (% i 5) 0))"},
+		{"a | b ^ c & d", "(| a (^ b (& c d)))"},
+		{"a & b | c ^ d", "(| (& a b) (^ c d))"},
+		{"1 << 2 + 3", "(<< 1 (+ 2 3))"},
+		{"a >> 1 & b << 2", "(& (>> a 1) (<< b 2))"},
+		{"a | b == c", "(== (| a b) c)"},
+		{"a < b | c", "(< a (| b c))"},
+		{"2 * 3 ** 2", "(* 2 (** 3 2))"},
+		{"2 ** 3 ** 2", "(** 2 (** 3 2))"},
+		{"-2 ** 2", "(- (** 2 2))"},
+		{"2 ** -1", "(** 2 (- 1))"},
+		{"~a & b", "(& (~ a) b)"},
+		{"xs[0] ** f(2)", "(** (index xs 0) (call f 2))"},
+		{"-a + b", "(+ (- a) b)"},
+		{"-a * -b", "(* (- a) (- b))"},
+		{"- -a", "(- (- a))"},
+		{"!a == b", "(== (! a) b)"},
+		{"!a && b", "(&& (! a) b)"},
+		{"-(1 + 2) * 3", "(* (- (group (+ 1 2))) 3)"},
+		{"(1 + 2) * 3", "(* (group (+ 1 2)) 3)"},
+		{"1 + (2 - 3) - 4", "(- (+ 1 (group (- 2 3))) 4)"},
+		{"xs[0] + xs[1] * 2", "(+ (index xs 0) (* (index xs 1) 2))"},
+		{"f(1) + g(2, 3) * 2", "(+ (call f 1) (* (call g 2 3) 2))"},
+		{"n * fact(n - 1)", "(* n (call fact (- n 1)))"},
+		{"f(a) > 3 && !g()", "(&& (> (call f a) 3) (! (call g)))"},
+		{"f(1)(2) - xs[0](h(3))", "(- (call (call f 1) 2) (call (index xs 0) (call h 3)))"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			got, err := parseExprString(t, tt.src)
+			if err != nil {
+				t.Fatalf("unexpected parser error: %s", err.Error())
+			}
+
+			if got != tt.expected {
+				t.Errorf("got %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAssignmentExprs(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{"i++", "(i ++)"},
+		{"--i", "(-- i)"},
+		{"-i++", "(- (i ++))"},
+		{"!--i", "(! (-- i))"},
+		{"++i ** 2", "(** (++ i) 2)"},
+		{"xs[i++]", "(index xs (i ++))"},
+		{"f(i--, ++j)", "(call f (i --) (++ j))"},
+		{"a + b++ * 2", "(+ a (* (b ++) 2))"},
+		{"total += 2 * x", "(+= total (* 2 x))"},
+		{"a -= b -= 1", "(-= a (-= b 1))"},
+		{"n *= n + 1", "(*= n (+ n 1))"},
+		{"n /= 2", "(/= n 2)"},
+		{"n %= 3 == 0", "(%= n (== 3 0))"},
+		{"f(n += 1)", "(call f (+= n 1))"},
+		{"(n += 1) * 2", "(* (group (+= n 1)) 2)"},
+		{"x = y = 3", "(= x (= y 3))"},
+		{"f(x = 1)", "(call f (= x 1))"},
+		{"x = y += 1", "(= x (+= y 1))"},
+		{"xs[i] = v + 1", "(= (index xs i) (+ v 1))"},
+		{"m[k] = xs[0] = 2", "(= (index m k) (= (index xs 0) 2))"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			got, err := parseExprString(t, tt.src)
+			if err != nil {
+				t.Fatalf("unexpected parser error: %s", err.Error())
+			}
+
+			if got != tt.expected {
+				t.Errorf("got %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestInvalidAssignmentTarget(t *testing.T) {
+	tests := []struct {
+		src      string
+		operator string
+	}{
+		{"xs[0] += 1", "+="},
+		{"a + b -= 1", "-="},
+		{"f() *= 2", "*="},
+		{"(a) /= 2", "/="},
+		{"1 %= 2", "%="},
+		{"xs[0]++", "++"},
+		{"f()--", "--"},
+		{"++xs[0]", "++"},
+		{"--(a)", "--"},
+		{"++i++", "++"},
+		{"3++", "++"},
+		{"a + b = 1", "="},
+		{"f() = 2", "="},
+		{"1 = 2", "="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := parseExprString(t, tt.src)
+			if err == nil {
+				t.Fatalf("expected a parser error")
+			}
+
+			if err.At != tt.operator || err.Diagnostic().Code != "P022" {
+				t.Errorf("got %q at %q, expected an invalid target error at %q", err.Message, err.At, tt.operator)
+			}
+		})
+	}
+}
+
+func TestForUpdateClause(t *testing.T) {
+	valid := []string{"i = i + 2", "i += 2", "i++", "--i"}
+	for _, update := range valid {
+		t.Run(update, func(t *testing.T) {
+			if _, errs := buildAst(t, "chillin (rizz i = 0; i < 10; "+update+") { yap(i); }"); errs != nil {
+				t.Errorf("unexpected parser error: %s", errs.Error())
+			}
+		})
+	}
+
+	invalid := []string{"i + 2", "yap(i)", "xs[0] += 1"}
+	for _, update := range invalid {
+		t.Run(update, func(t *testing.T) {
+			if _, errs := buildAst(t, "chillin (rizz i = 0; i < 10; "+update+") { yap(i); }"); errs == nil {
+				t.Errorf("expected a parser error")
+			}
+		})
+	}
+}
+
+func TestExprMissingOperand(t *testing.T) {
+	tests := []string{
+		"1 +",
+		"1 + 2 *",
+		"a &&",
+		"-",
+		"!",
+		"a +=",
+		"++",
+	}
+
+	for _, src := range tests {
+		t.Run(src, func(t *testing.T) {
+			if _, err := parseExprString(t, src); err == nil {
+				t.Errorf("expected a parser error")
+			}
+		})
+	}
+}
+
+func TestLoopControlOutsideLoop(t *testing.T) {
+	tests := []string{
+		"yeet;",
+		"skrrt;",
+		"edging (bet) { yeet; }",
+		"vibin (bet) { skibidi f() { skrrt; } }",
+	}
+
+	for _, src := range tests {
+		t.Run(src, func(t *testing.T) {
+			if _, err := parseExprString(t, src); err == nil {
+				t.Errorf("expected a parser error")
+			}
+		})
+	}
+}
+
+// functions are bound when they are run, so declaring one twice is only caught at runtime
+func TestFuncDeclarationsAreNotBoundWhileParsing(t *testing.T) {
+	src := "skibidi f() { bussin 1; } skibidi f() { bussin 2; }"
+
+	tkns, lexErr := helpers.ProcessTokens(lexer.NewLexer([]byte(src)))
+	if lexErr != nil {
+		t.Fatalf("lexing %q: %s", src, lexErr.Error())
+	}
+
+	program, err := NewParser(tkns).BuildAst()
+	if err != nil {
+		t.Fatalf("unexpected parser error: %s", err.Error())
+	}
+
+	if len(program) != 2 {
+		t.Fatalf("got %d nodes, expected 2", len(program))
+	}
+}
+
+func TestNodeSpans(t *testing.T) {
+	src := "rizz total = 1;\n  yap(total + f(2));"
+
+	tkns, lexErr := helpers.ProcessTokens(lexer.NewLexer([]byte(src)))
+	if lexErr != nil {
+		t.Fatalf("lexing %q: %s", src, lexErr.Error())
+	}
+
+	program, err := NewParser(tkns).BuildAst()
+	if err != nil {
+		t.Fatalf("unexpected parser error: %s", err.Error())
+	}
+
+	sum := program[1].Value.(ast.PrintStmt).Node.Value.(ast.GroupingExpr).Node.Value.(ast.BinaryExpr)
+
+	tests := []struct {
+		node     ast.AstNodeValue
+		start    string
+		end      string
+		expected string
+	}{
+		{program[0].Value, "1:1", "1:16", "rizz total = 1;"},
+		{program[1].Value, "2:3", "2:21", "yap(total + f(2));"},
+		{sum, "2:7", "2:19", "total + f(2)"},
+		{sum.Right.(ast.CallExpr), "2:15", "2:19", "f(2)"},
+	}
+
+	for _, tt := range tests {
+		span := tt.node.GetSpan()
+
+		if span.Start.Format("") != tt.start || span.End.Format("") != tt.end {
+			t.Errorf("%T: got %s-%s, expected %s-%s", tt.node, span.Start.Format(""), span.End.Format(""), tt.start, tt.end)
+		}
+
+		if got := src[span.Start.Offset:span.End.Offset]; got != tt.expected {
+			t.Errorf("%T: spans %q, expected %q", tt.node, got, tt.expected)
+		}
+	}
+}
+
+func buildAst(t *testing.T, src string) (ast.Ast, ParserErrors) {
+	t.Helper()
+
+	tkns, lexErr := helpers.ProcessTokens(lexer.NewLexer([]byte(src)))
+	if lexErr != nil {
+		t.Fatalf("lexing %q: %s", src, lexErr.Error())
+	}
+
+	return NewParser(tkns).BuildAst()
+}
+
+func TestParserErrorPosition(t *testing.T) {
+	_, errs := buildAst(t, "yap(1);\n  yap(total +\n    2);")
+	if len(errs) == 0 {
+		t.Fatalf("expected a parser error")
+	}
+
+	if got := errs[0].Span.Start.Format("main.brt"); got != "main.brt:2:13" {
+		t.Errorf("got error at %s, expected main.brt:2:13", got)
+	}
+}
+
+// errors within call arguments, conditions and bodies are reported as is rather than replaced
+func TestNestedErrorsAreKept(t *testing.T) {
+	tests := []struct {
+		src     string
+		message diagnostics.Message
+		at      string
+	}{
+		{`len("x ${1 + } y");`, EXPRESSION_EXPECTED, "1:14"},
+		{"a = (1;", MISSING_RPAREN, "1:6"},
+		{"vibin (xs[) { yap(1); }", MISSING_RBRACKET, "1:11"},
+		{"vibin (bet) { yap(1 +); }", MISSING_RPAREN, "1:22"},
+		{"skibidi f() { yap(1 +); }", MISSING_RPAREN, "1:22"},
+		{"chillin (rizz i = 0; i < (1 +); i++) {}", MISSING_RPAREN, "1:30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, errs := buildAst(t, tt.src)
+			if len(errs) == 0 {
+				t.Fatalf("expected a parser error")
+			}
+
+			if errs[0].Code != tt.message.Code || errs[0].Message != tt.message.Text || errs[0].Span.Start.Format("") != tt.at {
+				t.Errorf("got %s %q at %s, expected %s %q at %s", errs[0].Code, errs[0].Message, errs[0].Span.Start.Format(""), tt.message.Code, tt.message.Text, tt.at)
+			}
+		})
+	}
+}
+
+func TestArgumentsLimit(t *testing.T) {
+	for _, count := range []int{255, 256} {
+		names := make([]string, count)
+		for i := range names {
+			names[i] = fmt.Sprintf("a%d", i)
+		}
+
+		list := strings.Join(names, ", ")
+
+		for _, src := range []string{"f(" + list + ");", "skibidi f(" + list + ") {}"} {
+			_, errs := buildAst(t, src)
+
+			if tooMany := len(errs) > 0 && errs[0].Message == TOO_MANY_ARGUMENTS.Text; tooMany != (count > 255) {
+				t.Errorf("%d arguments: got errors %v", count, errs)
+			}
+		}
+	}
+}
+
+func TestUnclosedDelimiterPointsAtOpening(t *testing.T) {
+	_, errs := buildAst(t, "yap(0);\n  {\n  yap(1);\n")
+	if len(errs) != 1 || errs[0].Message != MISSING_RBRACE.Text {
+		t.Fatalf("got %v, expected a missing '}' error", errs)
+	}
+
+	err := errs[0]
+
+	if len(err.Notes) != 1 || err.Notes[0].Span == nil || err.Notes[0].Span.Start.Format("") != "2:3" {
+		t.Errorf("got notes %+v, expected one pointing at the '{' at 2:3", err.Notes)
+	}
+
+	if code := err.Diagnostic().Code; code != "P009" {
+		t.Errorf("got code %s, expected P009", code)
+	}
+}
+
+func TestInterpolatedStrExpr(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{`"score: ${score * 2}!"`, `(interpolate "score: " (* score 2) "!")`},
+		{`"${a}${b}"`, `(interpolate a b)`},
+		{`"a ${"b ${c} d"} e"`, `(interpolate "a " (interpolate "b " c " d") " e")`},
+		{`"${ {"k": 1}["k"] }"`, `(interpolate (index {k: 1} k))`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			got, err := parseExprString(t, tt.src)
+			if err != nil {
+				t.Fatalf("unexpected parser error: %s", err.Error())
+			}
+
+			if got != tt.expected {
+				t.Errorf("got %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestInterpolationErrorPosition(t *testing.T) {
+	tests := []struct {
+		src     string
+		message diagnostics.Message
+		at      string
+	}{
+		{`yap("total: ${1 +} left");`, EXPRESSION_EXPECTED, "1:18"},
+		{`yap("a ${} b");`, EXPRESSION_EXPECTED, "1:10"},
+		{`yap("a ${x y} b");`, MISSING_RBRACE, "1:10"},
+		{"yap(1);\nyap(\"${a} and ${b *} \");", EXPRESSION_EXPECTED, "2:20"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, errs := buildAst(t, tt.src)
+			if len(errs) != 1 {
+				t.Fatalf("got %v, expected a single parser error", errs)
+			}
+
+			if errs[0].Message != tt.message.Text {
+				t.Errorf("got %q, expected %q", errs[0].Message, tt.message.Text)
+			}
+
+			if got := errs[0].Span.Start.Format(""); got != tt.at {
+				t.Errorf("got error at %s, expected %s", got, tt.at)
+			}
+		})
+	}
+}
+
+func TestBuildAstReportsEveryError(t *testing.T) {
+	src := `rizz a = ;
+yap(a)
+rizz b = 2;
+skibidi f() {
+  rizz c = ;
+  yeet;
+  bussin c;
+}
+edging (a > 1 { yap(1); }
+yap(b);`
+
+	_, errs := buildAst(t, src)
+
+	expected := []struct {
+		line    int
+		message diagnostics.Message
+	}{
+		{1, EXPRESSION_AFTER_ASSIGNMENT_EXPECTED},
+		{2, MISSING_SEMICOLON},
+		{5, EXPRESSION_AFTER_ASSIGNMENT_EXPECTED},
+		{6, BREAK_OUTSIDE_LOOP},
+		{9, MISSING_RPAREN},
+	}
+
+	if len(errs) != len(expected) {
+		t.Fatalf("got %d errors, expected %d:\n%s", len(errs), len(expected), errs.Error())
+	}
+
+	for i, err := range errs {
+		if err.Span.Start.Line != expected[i].line || err.Message != expected[i].message.Text {
+			t.Errorf("error %d: got %q on line %d, expected %q on line %d", i, err.Message, err.Span.Start.Line, expected[i].message.Text, expected[i].line)
+		}
+	}
+}
+
+func TestBuildAstWithoutErrors(t *testing.T) {
+	program, errs := buildAst(t, "rizz a = 1; { yap(a); }")
+	if errs != nil {
+		t.Fatalf("unexpected parser errors: %s", errs.Error())
+	}
+
+	if len(program) != 2 {
+		t.Errorf("got %d nodes, expected 2", len(program))
+	}
+}
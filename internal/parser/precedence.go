@@ -0,0 +1,52 @@
+package parser
+
+import "github.com/0xmukesh/interpreter/internal/tokens"
+
+type Precedence int
+
+// binding power of binary operators, from loosest to tightest. `**` binds tighter than all of them
+// and than prefix operators, so it's parsed by powerRule instead
+const (
+	LOWEST Precedence = iota
+	LOGICAL_OR
+	LOGICAL_AND
+	EQUALITY
+	COMPARISON
+	BITWISE_OR
+	BITWISE_XOR
+	BITWISE_AND
+	SHIFT
+	TERM
+	FACTOR
+)
+
+var binaryPrecedences = map[tokens.TokenType]Precedence{
+	tokens.OR:              LOGICAL_OR,
+	tokens.AND:             LOGICAL_AND,
+	tokens.EQUAL_EQUAL:     EQUALITY,
+	tokens.BANG_EQUAL:      EQUALITY,
+	tokens.LESS:            COMPARISON,
+	tokens.LESS_EQUAL:      COMPARISON,
+	tokens.GREATER:         COMPARISON,
+	tokens.GREATER_EQUAL:   COMPARISON,
+	tokens.BIT_OR:          BITWISE_OR,
+	tokens.BIT_XOR:         BITWISE_XOR,
+	tokens.BIT_AND:         BITWISE_AND,
+	tokens.LESS_LESS:       SHIFT,
+	tokens.GREATER_GREATER: SHIFT,
+	tokens.PLUS:            TERM,
+	tokens.MINUS:           TERM,
+	tokens.STAR:            FACTOR,
+	tokens.SLASH:           FACTOR,
+	tokens.MODULO:          FACTOR,
+}
+
+// binary operator applied by each compound assignment operator. compound assignments bind looser
+// than every binary operator and associate to the right, so they're parsed by expressionRule itself
+var compoundOperators = map[tokens.TokenType]tokens.TokenType{
+	tokens.PLUS_EQUAL:   tokens.PLUS,
+	tokens.MINUS_EQUAL:  tokens.MINUS,
+	tokens.STAR_EQUAL:   tokens.STAR,
+	tokens.SLASH_EQUAL:  tokens.SLASH,
+	tokens.MODULO_EQUAL: tokens.MODULO,
+}
@@ -0,0 +1,433 @@
+package resolver
+
+import (
+	"github.com/0xmukesh/interpreter/internal/ast"
+	"github.com/0xmukesh/interpreter/internal/tokens"
+)
+
+type variable struct {
+	name string
+	slot int
+	span tokens.Span
+	// unset while the variable's initializer is resolved, reading the variable there is an error
+	isDefined bool
+	// set for a function declared further down in its block. its slot is reserved upfront so
+	// functions declared earlier can call it, but code running before the declaration can't see it
+	isPending bool
+	isUsed    bool
+	isParam   bool
+}
+
+// scope mirrors an environment the runner creates, a block or the parameters of a function
+type scope struct {
+	vars []*variable
+	// set for the scope holding a function's parameters
+	isFunction bool
+}
+
+func (s *scope) lookup(name string) *variable {
+	for _, v := range s.vars {
+		if v.name == name {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// a global declared by the program
+type global struct {
+	span tokens.Span
+	// unset while the global's initializer is resolved
+	isDefined bool
+}
+
+// a global used by the program, it's only known to be undefined once the whole program is resolved
+type globalRef struct {
+	name string
+	span tokens.Span
+	// set if the global is used within a function's body
+	inFunction bool
+}
+
+// Resolver binds every variable of a program to the environment and slot it lives in, so the runner
+// doesn't have to look variables up by name. variables declared outside of any block are globals and
+// are still looked up by name
+type Resolver struct {
+	scopes []*scope
+	// globals declared by the program
+	globals    map[string]*global
+	globalRefs []globalRef
+	// reports whether a global exists before the program runs, such as a native function or a
+	// variable declared by an earlier program run against the same runtime
+	isKnownGlobal func(name string) bool
+	// set when the program is one of several run against the same runtime, such as the lines of a
+	// REPL session. functions can then use globals which a later program declares, using one which
+	// doesn't exist yet is a runtime error once the function is called
+	Incremental bool
+	warnings    []*ResolverError
+}
+
+func NewResolver(isKnownGlobal func(name string) bool) *Resolver {
+	return &Resolver{
+		globals:       make(map[string]*global),
+		isKnownGlobal: isKnownGlobal,
+	}
+}
+
+// Resolve fills in the bindings of the program's nodes. it returns the warnings about the program,
+// or the first error which keeps it from running
+func (r *Resolver) Resolve(program ast.Ast) ([]*ResolverError, *ResolverError) {
+	for _, node := range program {
+		if err := r.resolveNode(node); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, ref := range r.globalRefs {
+		if ref.inFunction && r.Incremental {
+			continue
+		}
+
+		if _, ok := r.globals[ref.name]; !ok && !r.isKnownGlobal(ref.name) {
+			return nil, NewResolverError(UNDEFINED_IDENTIFIER, ref.name, ref.span)
+		}
+	}
+
+	return r.warnings, nil
+}
+
+func (r *Resolver) beginScope(isFunction bool) {
+	r.scopes = append(r.scopes, &scope{isFunction: isFunction})
+}
+
+// drops the innermost scope, warning about its locals which were never read
+func (r *Resolver) endScope() {
+	s := r.scopes[len(r.scopes)-1]
+	r.scopes = r.scopes[:len(r.scopes)-1]
+
+	for _, v := range s.vars {
+		if !v.isUsed && !v.isParam {
+			r.warnings = append(r.warnings, NewResolverWarning(UNUSED_LOCAL, v.name, v.span))
+		}
+	}
+}
+
+// declares a variable in the innermost scope, or a global if there is none
+func (r *Resolver) declare(name string, span tokens.Span) (*variable, *ResolverError) {
+	if len(r.scopes) == 0 {
+		if g, ok := r.globals[name]; ok {
+			return nil, NewResolverError(IDENTIFIER_ALREADY_EXISTS, name, span).WithNote(FIRST_DECLARED_HERE_NOTE, &g.span)
+		}
+
+		r.globals[name] = &global{span: span}
+		return nil, nil
+	}
+
+	s := r.scopes[len(r.scopes)-1]
+	if v := s.lookup(name); v != nil {
+		return nil, NewResolverError(IDENTIFIER_ALREADY_EXISTS, name, span).WithNote(FIRST_DECLARED_HERE_NOTE, &v.span)
+	}
+
+	v := &variable{name: name, slot: len(s.vars), span: span}
+	s.vars = append(s.vars, v)
+
+	return v, nil
+}
+
+// marks a declared variable as readable, `v` is nil for globals
+func (r *Resolver) define(name string, v *variable) {
+	if v == nil {
+		r.globals[name].isDefined = true
+		return
+	}
+
+	v.isDefined = true
+}
+
+// fills in `binding` with where the variable declared by `v` lives, `v` is nil for globals
+func bindDeclaration(binding *ast.Binding, v *variable) {
+	if v == nil {
+		binding.Depth = -1
+		return
+	}
+
+	binding.Depth = 0
+	binding.Slot = v.slot
+}
+
+// finds the variable `name` refers to from the innermost scope and fills in `binding`. pending
+// functions are only visible from within other functions
+func (r *Resolver) resolveName(name string, span tokens.Span, binding *ast.Binding, isRead bool) *ResolverError {
+	crossedFunction := false
+
+	for i := len(r.scopes) - 1; i >= 0; i-- {
+		s := r.scopes[i]
+
+		if v := s.lookup(name); v != nil && (crossedFunction || !v.isPending) {
+			if isRead && !v.isDefined && !crossedFunction {
+				return NewResolverError(READ_IN_OWN_INITIALIZER, name, span).WithNote(DECLARED_HERE_NOTE, &v.span)
+			}
+
+			if isRead {
+				v.isUsed = true
+			}
+
+			if binding != nil {
+				binding.Depth = len(r.scopes) - 1 - i
+				binding.Slot = v.slot
+			}
+
+			return nil
+		}
+
+		if s.isFunction {
+			crossedFunction = true
+		}
+	}
+
+	if g, ok := r.globals[name]; ok && !g.isDefined && isRead && len(r.scopes) == 0 {
+		return NewResolverError(READ_IN_OWN_INITIALIZER, name, span).WithNote(DECLARED_HERE_NOTE, &g.span)
+	}
+
+	if binding != nil {
+		binding.Depth = -1
+	}
+
+	// every scope was searched, so `crossedFunction` tells whether the global is used within a function
+	r.globalRefs = append(r.globalRefs, globalRef{name: name, span: span, inFunction: crossedFunction})
+	return nil
+}
+
+// reserves a slot for every function declared directly within a block, so functions declared earlier
+// can call the ones declared later
+func (r *Resolver) declareFuncs(nodes []ast.AstNode) *ResolverError {
+	for _, node := range nodes {
+		declaration, ok := node.Value.(ast.FuncDeclarationStmt)
+		if !ok {
+			continue
+		}
+
+		v, err := r.declare(declaration.Name, declaration.Span)
+		if err != nil {
+			return err
+		}
+
+		v.isPending = true
+	}
+
+	return nil
+}
+
+// finds the slot `declareFuncs` reserved for the function, or declares it if there is none
+func (r *Resolver) declareFunc(declaration ast.FuncDeclarationStmt) (*variable, *ResolverError) {
+	if len(r.scopes) > 0 {
+		if v := r.scopes[len(r.scopes)-1].lookup(declaration.Name); v != nil && v.isPending {
+			return v, nil
+		}
+	}
+
+	return r.declare(declaration.Name, declaration.Span)
+}
+
+func (r *Resolver) resolveFunction(args []ast.AstNode, body ast.AstNode) *ResolverError {
+	r.beginScope(true)
+
+	for _, arg := range args {
+		literal := arg.Value.(ast.LiteralExpr)
+
+		v, err := r.declare(literal.Value, literal.Span)
+		if err != nil {
+			return err
+		}
+
+		v.isParam = true
+		r.define(literal.Value, v)
+		bindDeclaration(literal.Binding, v)
+	}
+
+	if err := r.resolveNode(body); err != nil {
+		return err
+	}
+
+	r.endScope()
+	return nil
+}
+
+func (r *Resolver) resolveNode(node ast.AstNode) *ResolverError {
+	switch v := node.Value.(type) {
+	case ast.Expr:
+		return r.resolveExpr(v)
+	case ast.PrintStmt:
+		return r.resolveNode(v.Node)
+	case ast.VarAssignStmt:
+		variable, err := r.declare(v.Name, v.Span)
+		if err != nil {
+			return err
+		}
+
+		if err := r.resolveNode(v.Node); err != nil {
+			return err
+		}
+
+		r.define(v.Name, variable)
+		bindDeclaration(v.Binding, variable)
+	case ast.CreateBlockStmt:
+		r.beginScope(false)
+
+		if err := r.declareFuncs(v.Nodes); err != nil {
+			return err
+		}
+
+		for _, n := range v.Nodes {
+			if err := r.resolveNode(n); err != nil {
+				return err
+			}
+		}
+
+		r.endScope()
+	case ast.IfStmt:
+		if err := r.resolveNode(v.Node); err != nil {
+			return err
+		}
+
+		if err := r.resolveNode(v.IfBranch); err != nil {
+			return err
+		}
+
+		if v.ElseIfBranches != nil {
+			for _, branch := range *v.ElseIfBranches {
+				if err := r.resolveNode(branch.Node); err != nil {
+					return err
+				}
+
+				if err := r.resolveNode(branch.Branch); err != nil {
+					return err
+				}
+			}
+		}
+
+		if v.ElseBranch != nil {
+			return r.resolveNode(v.ElseBranch.Branch)
+		}
+	case ast.WhileStmt:
+		if err := r.resolveNode(v.Node); err != nil {
+			return err
+		}
+
+		return r.resolveNode(v.Branch)
+	case ast.ForStmt:
+		// the variable declared by `init` lives in the enclosing scope, same as in the runner
+		for _, n := range []ast.AstNode{v.Init, v.Condition, v.Node, v.Update} {
+			if err := r.resolveNode(n); err != nil {
+				return err
+			}
+		}
+	case ast.FuncDeclarationStmt:
+		variable, err := r.declareFunc(v)
+		if err != nil {
+			return err
+		}
+
+		// the function can see itself, so it can be recursive
+		if variable != nil {
+			variable.isPending = false
+		}
+
+		r.define(v.Name, variable)
+		bindDeclaration(v.Binding, variable)
+
+		return r.resolveFunction(v.Args, v.Node)
+	case ast.ReturnStmt:
+		return r.resolveNode(v.Node)
+	}
+
+	return nil
+}
+
+func (r *Resolver) resolveExpr(expr ast.Expr) *ResolverError {
+	switch v := expr.(type) {
+	case ast.LiteralExpr:
+		if v.TokenType == tokens.IDENTIFIER {
+			return r.resolveName(v.Value, v.Span, v.Binding, true)
+		}
+	case ast.GroupingExpr:
+		return r.resolveNode(v.Node)
+	case ast.UnaryExpr:
+		return r.resolveExpr(v.Expr)
+	case ast.BinaryExpr:
+		if err := r.resolveExpr(v.Left); err != nil {
+			return err
+		}
+
+		return r.resolveExpr(v.Right)
+	case ast.LogicalExpr:
+		if err := r.resolveExpr(v.Left); err != nil {
+			return err
+		}
+
+		return r.resolveExpr(v.Right)
+	case ast.FuncExpr:
+		return r.resolveFunction(v.Args, v.Node)
+	case ast.CallExpr:
+		if err := r.resolveNode(v.Callee); err != nil {
+			return err
+		}
+
+		for _, arg := range v.Args {
+			if err := r.resolveNode(arg); err != nil {
+				return err
+			}
+		}
+	case ast.ListExpr:
+		for _, element := range v.Elements {
+			if err := r.resolveNode(element); err != nil {
+				return err
+			}
+		}
+	case ast.MapExpr:
+		for _, entry := range v.Entries {
+			if err := r.resolveNode(entry.Key); err != nil {
+				return err
+			}
+
+			if err := r.resolveNode(entry.Value); err != nil {
+				return err
+			}
+		}
+	case ast.IndexExpr:
+		if err := r.resolveNode(v.Node); err != nil {
+			return err
+		}
+
+		return r.resolveNode(v.Index)
+	case ast.IncrementExpr:
+		return r.resolveName(v.Name, v.Span, v.Binding, true)
+	case ast.CompoundAssignExpr:
+		if err := r.resolveExpr(v.Value); err != nil {
+			return err
+		}
+
+		return r.resolveName(v.Name, v.Span, v.Binding, true)
+	case ast.AssignExpr:
+		if err := r.resolveExpr(v.Value); err != nil {
+			return err
+		}
+
+		return r.resolveName(v.Name, v.Span, v.Binding, false)
+	case ast.IndexAssignExpr:
+		if err := r.resolveExpr(v.Target); err != nil {
+			return err
+		}
+
+		return r.resolveExpr(v.Value)
+	case ast.InterpolatedStrExpr:
+		for _, part := range v.Parts {
+			if err := r.resolveNode(part); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
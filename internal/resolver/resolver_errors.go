@@ -0,0 +1,86 @@
+package resolver
+
+import (
+	"fmt"
+
+	"github.com/0xmukesh/interpreter/internal/diagnostics"
+	"github.com/0xmukesh/interpreter/internal/tokens"
+)
+
+var (
+	UNDEFINED_IDENTIFIER      = diagnostics.Message{Code: "R001", Text: "damn bruv, this identifier got that invisible drip"}
+	IDENTIFIER_ALREADY_EXISTS = diagnostics.Message{Code: "R002", Text: "nah, the sequel ain't happening for this identifier"}
+	READ_IN_OWN_INITIALIZER   = diagnostics.Message{Code: "R003", Text: "bro is using the variable to define itself, that's not how this works"}
+	UNUSED_LOCAL              = diagnostics.Message{Code: "R004", Text: "declared it and ghosted it, this variable is never used"}
+)
+
+const (
+	DECLARED_HERE_NOTE       = "it's declared here"
+	FIRST_DECLARED_HERE_NOTE = "it's first declared here"
+)
+
+// ResolverError is an error or a warning found while resolving the variables of a program. errors
+// stop the program from running, warnings are only reported
+type ResolverError struct {
+	Code      string
+	Message   string
+	At        string
+	Span      tokens.Span
+	IsWarning bool
+	Notes     []diagnostics.Note
+	// name of the file being resolved, it's empty when the source doesn't come from a file
+	File string
+}
+
+func NewResolverError(msg diagnostics.Message, at string, span tokens.Span) *ResolverError {
+	return &ResolverError{
+		Code:    msg.Code,
+		Message: msg.Text,
+		At:      at,
+		Span:    span,
+	}
+}
+
+func NewResolverWarning(msg diagnostics.Message, at string, span tokens.Span) *ResolverError {
+	return &ResolverError{
+		Code:      msg.Code,
+		Message:   msg.Text,
+		At:        at,
+		Span:      span,
+		IsWarning: true,
+	}
+}
+
+// adds a note to the error, `span` is nil if the note doesn't point at the source
+func (e *ResolverError) WithNote(msg string, span *tokens.Span) *ResolverError {
+	e.Notes = append(e.Notes, diagnostics.Note{Message: msg, Span: span})
+	return e
+}
+
+func (e ResolverError) label() string {
+	if e.IsWarning {
+		return fmt.Sprintf("lowkey sus, the resolver has a warning at '%s'", e.At)
+	}
+
+	return fmt.Sprintf("hell naw, im done with you. you caused a resolver error at '%s'", e.At)
+}
+
+func (e ResolverError) Error() string {
+	return fmt.Sprintf("[%s] %s: %s", e.Span.Start.Format(e.File), e.label(), e.Message)
+}
+
+func (e ResolverError) Diagnostic() diagnostics.Diagnostic {
+	severity := diagnostics.ERROR
+	if e.IsWarning {
+		severity = diagnostics.WARNING
+	}
+
+	return diagnostics.Diagnostic{
+		Severity: severity,
+		Code:     e.Code,
+		Message:  e.Message,
+		Label:    e.label(),
+		Span:     e.Span,
+		Notes:    e.Notes,
+	}
+}
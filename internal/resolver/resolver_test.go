@@ -0,0 +1,149 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/0xmukesh/interpreter/internal/ast"
+	"github.com/0xmukesh/interpreter/internal/diagnostics"
+	"github.com/0xmukesh/interpreter/internal/helpers"
+	"github.com/0xmukesh/interpreter/internal/lexer"
+	"github.com/0xmukesh/interpreter/internal/parser"
+)
+
+func parseProgram(t *testing.T, src string) ast.Ast {
+	t.Helper()
+
+	tkns, lexErr := helpers.ProcessTokens(lexer.NewLexer([]byte(src)))
+	if lexErr != nil {
+		t.Fatalf("lexing %q: %s", src, lexErr.Error())
+	}
+
+	program, parseErr := parser.NewParser(tkns).BuildAst()
+	if parseErr != nil {
+		t.Fatalf("parsing %q: %s", src, parseErr.Error())
+	}
+
+	return program
+}
+
+func resolve(t *testing.T, src string) ([]*ResolverError, *ResolverError) {
+	t.Helper()
+
+	isNative := func(name string) bool { return name == "len" }
+	return NewResolver(isNative).Resolve(parseProgram(t, src))
+}
+
+func TestResolverErrors(t *testing.T) {
+	tests := []struct {
+		src     string
+		message diagnostics.Message
+		at      string
+	}{
+		{"yap(nope);", UNDEFINED_IDENTIFIER, "nope"},
+		{"skibidi f() { bussin nope; }", UNDEFINED_IDENTIFIER, "nope"},
+		{"{ rizz a = 1; } yap(a);", UNDEFINED_IDENTIFIER, "a"},
+		{"rizz a = a;", READ_IN_OWN_INITIALIZER, "a"},
+		{"rizz a = 1; { rizz a = a + 1; yap(a); }", READ_IN_OWN_INITIALIZER, "a"},
+		{"{ rizz a = 1; rizz a = 2; yap(a); }", IDENTIFIER_ALREADY_EXISTS, "a"},
+		{"rizz a = 1; rizz a = 2;", IDENTIFIER_ALREADY_EXISTS, "a"},
+		{"{ skibidi f() {} rizz f = 1; }", IDENTIFIER_ALREADY_EXISTS, "f"},
+		{"skibidi f(a, a) {}", IDENTIFIER_ALREADY_EXISTS, "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := resolve(t, tt.src)
+			if err == nil {
+				t.Fatalf("expected a resolver error")
+			}
+
+			if err.Message != tt.message.Text || err.At != tt.at {
+				t.Errorf("got %q at %q, expected %q at %q", err.Message, err.At, tt.message.Text, tt.at)
+			}
+		})
+	}
+}
+
+func TestResolverAccepts(t *testing.T) {
+	tests := []string{
+		// globals can be used by functions declared before them
+		"skibidi f() { bussin g(); } skibidi g() { bussin 1; } yap(f());",
+		"skibidi f() { bussin later; } rizz later = 1; yap(f());",
+		// functions of the same block can call each other
+		"{ skibidi a() { bussin b(); } skibidi b() { bussin len([]); } yap(a()); }",
+		// a closure within the initializer can refer to the variable
+		"{ rizz f = skibidi() { bussin f; }; yap(f); }",
+		"rizz a = 1; { rizz b = a; yap(b); }",
+		"{ rizz a = 1; { rizz a = 2; yap(a); } yap(a); }",
+	}
+
+	for _, src := range tests {
+		t.Run(src, func(t *testing.T) {
+			warnings, err := resolve(t, src)
+			if err != nil {
+				t.Fatalf("unexpected resolver error: %s", err.Error())
+			}
+
+			if len(warnings) != 0 {
+				t.Errorf("unexpected warnings: %v", warnings)
+			}
+		})
+	}
+}
+
+func TestIncrementalResolverDefersGlobalsOfFunctions(t *testing.T) {
+	r := NewResolver(func(string) bool { return false })
+	r.Incremental = true
+
+	// a later program can still declare `later`
+	if _, err := r.Resolve(parseProgram(t, "skibidi f() { bussin later(); }")); err != nil {
+		t.Fatalf("unexpected resolver error: %s", err.Error())
+	}
+
+	r = NewResolver(func(string) bool { return false })
+	r.Incremental = true
+
+	_, err := r.Resolve(parseProgram(t, "yap(later);"))
+	if err == nil || err.Message != UNDEFINED_IDENTIFIER.Text || err.At != "later" {
+		t.Errorf("expected an undefined identifier error for a global used outside of functions, got %v", err)
+	}
+}
+
+func TestResolverWarnsAboutUnusedLocals(t *testing.T) {
+	warnings, err := resolve(t, "rizz global = 1; skibidi f(unusedParam) { rizz unused = 1; rizz used = 2; unused = used; }")
+	if err != nil {
+		t.Fatalf("unexpected resolver error: %s", err.Error())
+	}
+
+	if len(warnings) != 1 || warnings[0].Message != UNUSED_LOCAL.Text || warnings[0].At != "unused" {
+		t.Fatalf("got %v, expected a single warning about 'unused'", warnings)
+	}
+}
+
+func TestResolverBindsSlots(t *testing.T) {
+	program := parseProgram(t, "rizz g = 1; skibidi f(a) { rizz b = a; { yap(a + b + g); } }")
+	if _, err := NewResolver(func(string) bool { return false }).Resolve(program); err != nil {
+		t.Fatalf("unexpected resolver error: %s", err.Error())
+	}
+
+	// f's body is the block after its parameters, and the print is one block further in
+	body := program[1].Value.(ast.FuncDeclarationStmt).Node.Value.(ast.CreateBlockStmt)
+	inner := body.Nodes[1].Value.(ast.CreateBlockStmt)
+	sum := inner.Nodes[0].Value.(ast.PrintStmt).Node.Value.(ast.GroupingExpr).Node.Value.(ast.BinaryExpr)
+
+	a := sum.Left.(ast.BinaryExpr).Left.(ast.LiteralExpr).Binding
+	b := sum.Left.(ast.BinaryExpr).Right.(ast.LiteralExpr).Binding
+	g := sum.Right.(ast.LiteralExpr).Binding
+
+	if *a != (ast.Binding{Depth: 2, Slot: 0}) {
+		t.Errorf("a: got %+v", *a)
+	}
+
+	if *b != (ast.Binding{Depth: 1, Slot: 0}) {
+		t.Errorf("b: got %+v", *b)
+	}
+
+	if !g.IsGlobal() {
+		t.Errorf("g: got %+v, expected a global", *g)
+	}
+}
@@ -2,14 +2,25 @@ package runner
 
 import (
 	"fmt"
-	"os"
-	"time"
 
 	"github.com/0xmukesh/interpreter/internal/ast"
 	"github.com/0xmukesh/interpreter/internal/evaluator"
 	"github.com/0xmukesh/interpreter/internal/runtime"
 	"github.com/0xmukesh/interpreter/internal/tokens"
-	"github.com/0xmukesh/interpreter/internal/utils"
+)
+
+// controlSignal tells the nodes being run to stop early, since a statement nested within them
+// transfers control elsewhere
+type controlSignal int
+
+const (
+	SIGNAL_NONE controlSignal = iota
+	// a `bussin` is unwinding to the enclosing function call
+	SIGNAL_RETURN
+	// a `yeet` is unwinding to the enclosing loop, which stops
+	SIGNAL_BREAK
+	// a `skrrt` is unwinding to the enclosing loop, which moves on to its next iteration
+	SIGNAL_CONTINUE
 )
 
 type Runner struct {
@@ -17,15 +28,25 @@ type Runner struct {
 	Runtime   *runtime.Runtime
 	Evaluator *evaluator.Evaluator
 	Idx       int
+
+	signal      controlSignal
+	returnValue *runtime.RuntimeValue
+	// number of function calls currently being run
+	callDepth int
 }
 
 func NewRunner(ast ast.Ast, runtime *runtime.Runtime, evaluator *evaluator.Evaluator) *Runner {
-	return &Runner{
+	r := &Runner{
 		Ast:       ast,
 		Runtime:   runtime,
 		Evaluator: evaluator,
 		Idx:       0,
 	}
+
+	// lets the evaluator run the bodies of the functions it calls
+	evaluator.Runner = r
+
+	return r
 }
 
 func (r *Runner) IsAtEnd() bool {
@@ -46,131 +67,87 @@ func (r *Runner) curr() ast.AstNode {
 	}
 }
 
-func (r *Runner) EvalAndRunNode(expr ast.Expr, node ast.AstNode) bool {
+func (r *Runner) EvalAndRunNode(expr ast.Expr, node ast.AstNode) (bool, *runtime.RuntimeError) {
 	evaledCondition, err := r.Evaluator.EvaluateExpr(expr)
 	if err != nil {
-		err := runtime.NewRuntimeError(runtime.ExpectedExprErrBuilder("boolean"), expr.ParseExpr(), expr.GetLine())
-		utils.EPrint(err.Error())
+		return false, err
 	}
 
 	if evaledCondition != nil {
 		conditionVal := (*evaledCondition).Value
 		if conditionVal != true && conditionVal != false {
-			err := runtime.NewRuntimeError(runtime.ExpectedExprErrBuilder("boolean"), evaledCondition.String(), expr.GetLine())
-			utils.EPrint(err.Error())
+			return false, runtime.NewRuntimeError(runtime.ExpectedExprErrBuilder("boolean"), evaledCondition.String(), expr.GetSpan())
 		}
 
 		if conditionVal == true {
-			r.RunNode(node, r.Runtime.CurrEnv())
-			return true
+			if _, err := r.RunNode(node, r.Runtime.CurrEnv()); err != nil {
+				return false, err
+			}
+
+			return true, nil
 		}
 	}
 
-	return false
+	return false, nil
 }
 
-func (r *Runner) RunNode(node ast.AstNode, localEnv *runtime.Environment) *runtime.RuntimeValue {
+func (r *Runner) RunNode(node ast.AstNode, localEnv *runtime.Environment) (*runtime.RuntimeValue, *runtime.RuntimeError) {
 	expr, isExpr := node.Value.(ast.Expr)
 
 	if !isExpr {
 		switch value := node.Value.(type) {
 		case ast.PrintStmt:
-			val := r.RunNode(value.Node, r.Runtime.CurrEnv())
-			fmt.Println(val)
-		case ast.IncrementStmt:
-			currEnv := r.Runtime.CurrEnv()
-			val, env := currEnv.GetVar(value.Name)
-
-			if val == nil {
-				err := runtime.NewRuntimeError(runtime.UNDEFINED_IDENTIFIER, value.Name, expr.GetLine())
-				utils.EPrint(err.Error())
-			}
-
-			valNum, isNum := val.Value.(float64)
-			if !isNum {
-				err := runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("number"), value.Name, expr.GetLine())
-				utils.EPrint(err.Error())
-			}
-
-			env.SetVar(value.Name, *runtime.NewRuntimeValue(valNum + 1))
-		case ast.DecrementStmt:
-			currEnv := r.Runtime.CurrEnv()
-			val, env := currEnv.GetVar(value.Name)
-
-			if val == nil {
-				err := runtime.NewRuntimeError(runtime.UNDEFINED_IDENTIFIER, value.Name, expr.GetLine())
-				utils.EPrint(err.Error())
-			}
-
-			valNum, isNum := val.Value.(float64)
-			if !isNum {
-				err := runtime.NewRuntimeError(runtime.OperandsMustBeOfErrBuilder("number"), value.Name, expr.GetLine())
-				utils.EPrint(err.Error())
+			val, err := r.RunNode(value.Node, r.Runtime.CurrEnv())
+			if err != nil {
+				return nil, err
 			}
 
-			env.SetVar(value.Name, *runtime.NewRuntimeValue(valNum - 1))
+			fmt.Fprintln(r.Runtime.Stdout, val)
 		case ast.CreateBlockStmt:
 			if localEnv != nil {
-				returnVal := runtime.NewRuntimeValue(nil)
-
-				env := runtime.NewEnvironment(runtime.RuntimeVarMapping{}, runtime.RuntimeFuncMapping{}, localEnv)
-				r.Runtime.AddNewEnv(*env)
+				env := runtime.NewEnvironment(nil, localEnv)
+				r.Runtime.AddNewEnv(env)
 				for _, node := range value.Nodes {
-					val := r.RunNode(node, env)
+					if _, err := r.RunNode(node, env); err != nil {
+						return nil, err
+					}
 
-					stmt, ok := node.Value.(ast.Stmt)
-					if ok {
-						_, ok := stmt.(ast.ReturnStmt)
-						if ok {
-							returnVal = runtime.NewRuntimeValue(val)
-						}
+					// the block's `CloseBlockStmt` won't be reached, so its environment is dropped here
+					if r.signal != SIGNAL_NONE {
+						r.Runtime.RemoveLastEnv()
+						return nil, nil
 					}
 				}
-
-				return returnVal
 			}
 		case ast.CloseBlockStmt:
 			r.Runtime.RemoveLastEnv()
 		case ast.VarAssignStmt:
-			val := r.RunNode(value.Node, r.Runtime.CurrEnv())
+			val, err := r.RunNode(value.Node, r.Runtime.CurrEnv())
+			if err != nil {
+				return nil, err
+			}
 
 			if val != nil {
-				currEnv := r.Runtime.CurrEnv()
-				_, ok := currEnv.Vars[value.Name]
-				if ok {
-					err := runtime.NewRuntimeError(runtime.IDENTIFIER_ALREADY_EXISTS, value.Name, value.Line)
-					utils.EPrint(err.Error())
+				if !r.Evaluator.DefineVar(value.Name, *value.Binding, *runtime.NewRuntimeValue(val.Value)) {
+					return nil, runtime.NewRuntimeError(runtime.IDENTIFIER_ALREADY_EXISTS, value.Name, value.Span)
 				}
-
-				currEnv.SetVar(value.Name, *runtime.NewRuntimeValue(val.Value))
 			}
-		case ast.VarReassignStmt:
-			currEnv := r.Runtime.CurrEnv()
-			val, env := currEnv.GetVar(value.Name)
-
-			if val == nil {
-				err := runtime.NewRuntimeError(runtime.UNDEFINED_IDENTIFIER, value.Name, expr.GetLine())
-				utils.EPrint(err.Error())
-			}
-
-			exprVal, err := r.Evaluator.EvaluateExpr(value.Node.ExtractExpr())
+		case ast.IfStmt:
+			res, err := r.EvalAndRunNode(value.Node.ExtractExpr(), value.IfBranch)
 			if err != nil {
-				utils.EPrint(fmt.Sprintf("%s\n", err.Error()))
+				return nil, err
 			}
 
-			if exprVal != nil {
-				env.SetVar(value.Name, *exprVal)
-			}
-		case ast.IfStmt:
-			var res bool
-			res = r.EvalAndRunNode(value.Node.ExtractExpr(), value.IfBranch)
-
 			if !res {
 				elseIfBranches := value.ElseIfBranches
 
 				if elseIfBranches != nil {
 					for _, elseIfBranch := range *elseIfBranches {
-						res = r.EvalAndRunNode(elseIfBranch.Node.ExtractExpr(), elseIfBranch.Branch)
+						res, err = r.EvalAndRunNode(elseIfBranch.Node.ExtractExpr(), elseIfBranch.Branch)
+						if err != nil {
+							return nil, err
+						}
+
 						if res {
 							break
 						}
@@ -182,127 +159,182 @@ func (r *Runner) RunNode(node ast.AstNode, localEnv *runtime.Environment) *runti
 				elseBranch := value.ElseBranch
 
 				if elseBranch != nil {
-					r.EvalAndRunNode(ast.NewLiteralExpr(tokens.TRUE, "", 0), elseBranch.Branch)
+					if _, err := r.EvalAndRunNode(ast.NewLiteralExpr(tokens.TRUE, "", elseBranch.Span), elseBranch.Branch); err != nil {
+						return nil, err
+					}
 				}
 			}
 		case ast.WhileStmt:
 			for {
 				val, err := r.Evaluator.EvaluateExpr(value.Node.ExtractExpr())
 				if err != nil {
-					utils.EPrint(fmt.Sprintf("%s\n", err.Error()))
+					return nil, err
 				}
 
 				if val != nil {
 					conditionVal, isConditionBool := val.Value.(bool)
 
 					if !isConditionBool {
-						err := runtime.NewRuntimeError(runtime.ExpectedExprErrBuilder("bool"), value.Node.ExtractExpr().ParseExpr(), value.Node.ExtractExpr().GetLine())
-						utils.EPrint(err.Error())
+						return nil, runtime.NewRuntimeError(runtime.ExpectedExprErrBuilder("bool"), value.Node.ExtractExpr().ParseExpr(), value.Node.ExtractExpr().GetSpan())
 					}
 
 					if !conditionVal {
 						break
 					}
 
-					r.RunNode(value.Branch, r.Runtime.CurrEnv())
+					if _, err := r.RunNode(value.Branch, r.Runtime.CurrEnv()); err != nil {
+						return nil, err
+					}
+
+					if r.endLoopIteration() {
+						return nil, nil
+					}
 				}
 			}
 		case ast.ForStmt:
-			r.RunNode(value.Init, r.Runtime.CurrEnv())
+			if _, err := r.RunNode(value.Init, r.Runtime.CurrEnv()); err != nil {
+				return nil, err
+			}
 
 			for {
 				val, err := r.Evaluator.EvaluateExpr(value.Condition.ExtractExpr())
 				if err != nil {
-					utils.EPrint(err.Error())
+					return nil, err
 				}
 
 				if val != nil {
 					conditionVal, isConditionBool := val.Value.(bool)
 
 					if !isConditionBool {
-						err := runtime.NewRuntimeError(runtime.ExpectedExprErrBuilder("bool"), value.Node.ExtractExpr().ParseExpr(), value.Node.ExtractExpr().GetLine())
-						utils.EPrint(err.Error())
+						return nil, runtime.NewRuntimeError(runtime.ExpectedExprErrBuilder("bool"), value.Condition.ExtractExpr().ParseExpr(), value.Condition.ExtractExpr().GetSpan())
 					}
 
 					if !conditionVal {
 						break
 					}
 
-					r.RunNode(value.Node, r.Runtime.CurrEnv())
-					r.RunNode(value.Update, r.Runtime.CurrEnv())
+					if _, err := r.RunNode(value.Node, r.Runtime.CurrEnv()); err != nil {
+						return nil, err
+					}
+
+					if r.endLoopIteration() {
+						return nil, nil
+					}
+
+					if _, err := r.RunNode(value.Update, r.Runtime.CurrEnv()); err != nil {
+						return nil, err
+					}
 				}
 			}
-		case ast.FuncCallStmt:
-			currEnv := r.Runtime.CurrEnv()
-			funcMappingPtr, _ := currEnv.GetFunc(value.Name)
-
-			if funcMappingPtr == nil {
-				err := runtime.NewRuntimeError(runtime.UNDEFINED_IDENTIFIER, value.Name, value.Line)
-				utils.EPrint(err.Error())
+		case ast.FuncDeclarationStmt:
+			// the function is bound once its declaration is reached and closes over the environment
+			// it's declared in. the resolver reserves the slots of a block's functions upfront, so it can
+			// call functions declared after it in the same block
+			function := runtime.NewFunction(value.Name, value.Args, value.Node, r.Runtime.CurrEnv())
+			if !r.Evaluator.DefineVar(value.Name, *value.Binding, *runtime.NewRuntimeValue(function)) {
+				return nil, runtime.NewRuntimeError(runtime.IDENTIFIER_ALREADY_EXISTS, value.Name, value.Span)
 			}
-
-			funcMapping := *funcMappingPtr
-
-			if len(value.Args) != len(funcMapping.Args) {
-				err := runtime.NewRuntimeError(fmt.Sprintf("damn, do you even know how you count? the function expected %d arguments but you gave %d arguments", len(funcMapping.Args), len(value.Args)), value.Name, value.Line)
-				utils.EPrint(err.Error())
+		case ast.BreakStmt:
+			r.signal = SIGNAL_BREAK
+		case ast.ContinueStmt:
+			r.signal = SIGNAL_CONTINUE
+		case ast.ReturnStmt:
+			val, err := r.RunNode(value.Node, r.Runtime.CurrEnv())
+			if err != nil {
+				return nil, err
 			}
 
-			argsMapping := make(runtime.RuntimeVarMapping)
-			localEnv := runtime.NewEnvironment(argsMapping, nil, r.Runtime.CurrEnv())
-
-			for i, arg := range value.Args {
-				argName := funcMapping.Args[i].Value.(ast.LiteralExpr).Value
-				argValue, _ := r.Evaluator.EvaluateExpr(arg.Value.(ast.Expr))
-				argsMapping[argName] = *argValue
+			if val == nil {
+				val = runtime.NewRuntimeValue(nil)
 			}
 
-			localEnv.Vars = argsMapping
-			r.Runtime.AddNewEnv(*localEnv)
+			r.signal = SIGNAL_RETURN
+			r.returnValue = val
 
-			return r.RunNode(funcMapping.Node, r.Runtime.CurrEnv())
-		case ast.ReturnStmt:
-			val := r.RunNode(value.Node, r.Runtime.CurrEnv())
-			if val == nil {
-				os.Exit(0)
-			}
-			return val
-		case ast.NativeClockFnStmt:
-			return runtime.NewRuntimeValue(time.Now().Unix())
+			return val, nil
 		}
 	} else {
-		groupingExpr, ok := expr.(ast.GroupingExpr)
+		return r.Evaluator.EvaluateExpr(expr)
+	}
 
-		if ok {
-			if _, ok := groupingExpr.Node.Value.(ast.Stmt); ok {
-				val := r.RunNode(groupingExpr.Node, r.Runtime.CurrEnv())
-				return val
-			}
+	return nil, nil
+}
 
-			val, err := r.Evaluator.EvaluateExpr(groupingExpr)
-			if err != nil {
-				utils.EPrint(fmt.Sprintf("%s\n", err.Error()))
-			}
+// consumes the `yeet` or `skrrt` which ended a loop's iteration, if any, and reports whether the loop
+// has to stop. a `bussin` stops the loop too but is left for the enclosing function call
+func (r *Runner) endLoopIteration() bool {
+	switch r.signal {
+	case SIGNAL_BREAK:
+		r.signal = SIGNAL_NONE
+		return true
+	case SIGNAL_CONTINUE:
+		r.signal = SIGNAL_NONE
+		return false
+	case SIGNAL_RETURN:
+		return true
+	default:
+		return false
+	}
+}
 
-			return val
-		} else {
-			val, err := r.Evaluator.EvaluateExpr(expr)
-			if err != nil {
-				utils.EPrint(fmt.Sprintf("%s\n", err.Error()))
-			}
+// runs the body of `function` with `args` bound to its parameters. the arguments count is checked by
+// the caller
+func (r *Runner) RunFunc(function *runtime.Function, args []runtime.RuntimeValue, span tokens.Span) (*runtime.RuntimeValue, *runtime.RuntimeError) {
+	// the top level counts as a call, same as the vm's frame for the script
+	if r.callDepth+1 >= runtime.MAX_CALL_DEPTH {
+		return nil, runtime.NewRuntimeError(runtime.STACK_OVERFLOW, function.String(), span)
+	}
 
-			return val
-		}
+	r.callDepth++
+	defer func() { r.callDepth-- }()
+
+	// the body runs in the environment the function was created in, not the one it is called from.
+	// parameters take the first slots of the function's own environment
+	localEnv := runtime.NewEnvironment(nil, function.Closure)
+	for i, arg := range args {
+		localEnv.DefineAt(i, arg)
+	}
+
+	r.Runtime.AddNewEnv(localEnv)
+
+	if _, err := r.RunNode(function.Node, localEnv); err != nil {
+		return nil, err
 	}
 
-	return nil
+	r.Runtime.RemoveLastEnv()
+
+	// a function which ends without a `bussin` returns nada
+	val := runtime.NewRuntimeValue(nil)
+	if r.signal == SIGNAL_RETURN {
+		val = r.returnValue
+		r.signal = SIGNAL_NONE
+		r.returnValue = nil
+	}
+
+	return val, nil
 }
 
-func (r *Runner) Run() {
+// runs the current top-level node and returns the value it produced, if any
+func (r *Runner) Run() (*runtime.RuntimeValue, *runtime.RuntimeError) {
 	curr := r.curr()
 
 	if !r.IsAtEnd() {
-		r.RunNode(curr, r.Runtime.CurrEnv())
+		val, err := r.RunNode(curr, r.Runtime.CurrEnv())
+		if err != nil {
+			return nil, err
+		}
+
 		r.advance()
+
+		// a top-level `bussin` ends the program
+		if r.signal == SIGNAL_RETURN {
+			r.Idx = len(r.Ast)
+			r.signal = SIGNAL_NONE
+			val = r.returnValue
+		}
+
+		return val, nil
 	}
+
+	return nil, nil
 }
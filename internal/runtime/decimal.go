@@ -0,0 +1,146 @@
+package runtime
+
+import (
+	"math/big"
+	"strings"
+)
+
+// number of digits after the decimal point kept when a division doesn't end sooner
+const DIVISION_SCALE = 16
+
+var bigTen = big.NewInt(10)
+
+// Decimal is an exact decimal number, its value is `Unscaled` / 10^`Scale`. the scale is kept as
+// written, so 1.10d stays "1.10". decimals are never mutated, operations return new ones
+type Decimal struct {
+	Unscaled *big.Int
+	Scale    int
+}
+
+func NewDecimal(unscaled *big.Int, scale int) *Decimal {
+	return &Decimal{
+		Unscaled: unscaled,
+		Scale:    scale,
+	}
+}
+
+// ParseDecimal parses decimal digits with an optional sign and decimal point, such as "-1.10"
+func ParseDecimal(s string) (*Decimal, error) {
+	whole, fraction, _ := strings.Cut(s, ".")
+
+	unscaled, ok := new(big.Int).SetString(whole+fraction, 10)
+	if !ok || strings.ContainsAny(fraction, "+-") {
+		return nil, INVALID_DECIMAL
+	}
+
+	return NewDecimal(unscaled, len(fraction)), nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+// returns the unscaled value of `d` at `scale`, which can't be smaller than the scale of `d`
+func (d *Decimal) rescale(scale int) *big.Int {
+	if scale == d.Scale {
+		return d.Unscaled
+	}
+
+	return new(big.Int).Mul(d.Unscaled, pow10(scale-d.Scale))
+}
+
+// returns the unscaled values of both decimals at the larger of their scales, and that scale
+func align(a *Decimal, b *Decimal) (*big.Int, *big.Int, int) {
+	scale := max(a.Scale, b.Scale)
+	return a.rescale(scale), b.rescale(scale), scale
+}
+
+func (d *Decimal) Add(other *Decimal) *Decimal {
+	a, b, scale := align(d, other)
+	return NewDecimal(new(big.Int).Add(a, b), scale)
+}
+
+func (d *Decimal) Sub(other *Decimal) *Decimal {
+	a, b, scale := align(d, other)
+	return NewDecimal(new(big.Int).Sub(a, b), scale)
+}
+
+func (d *Decimal) Mul(other *Decimal) *Decimal {
+	return NewDecimal(new(big.Int).Mul(d.Unscaled, other.Unscaled), d.Scale+other.Scale)
+}
+
+// divides `d` by `other`, which can't be zero. the result is rounded half away from zero to
+// DIVISION_SCALE digits, and trailing zeros are dropped down to the larger scale of the operands
+func (d *Decimal) Quo(other *Decimal) *Decimal {
+	scale := max(DIVISION_SCALE, d.Scale, other.Scale)
+
+	// d / other = (d.Unscaled * 10^(other.Scale + scale - d.Scale) / other.Unscaled) / 10^scale
+	num := new(big.Int).Mul(d.Unscaled, pow10(other.Scale+scale-d.Scale))
+	quo, rem := new(big.Int).QuoRem(num, other.Unscaled, new(big.Int))
+
+	if new(big.Int).Abs(new(big.Int).Lsh(rem, 1)).Cmp(new(big.Int).Abs(other.Unscaled)) >= 0 {
+		if num.Sign() == other.Unscaled.Sign() {
+			quo.Add(quo, big.NewInt(1))
+		} else {
+			quo.Sub(quo, big.NewInt(1))
+		}
+	}
+
+	return NewDecimal(quo, scale).trim(max(d.Scale, other.Scale))
+}
+
+// remainder of dividing `d` by `other`, which can't be zero. like `%` on ints it has the sign of `d`
+func (d *Decimal) Rem(other *Decimal) *Decimal {
+	a, b, scale := align(d, other)
+	return NewDecimal(new(big.Int).Rem(a, b), scale)
+}
+
+func (d *Decimal) Neg() *Decimal {
+	return NewDecimal(new(big.Int).Neg(d.Unscaled), d.Scale)
+}
+
+func (d *Decimal) Sign() int {
+	return d.Unscaled.Sign()
+}
+
+// compares the values of both decimals, whatever their scales are
+func (d *Decimal) Cmp(other *Decimal) int {
+	a, b, _ := align(d, other)
+	return a.Cmp(b)
+}
+
+// drops trailing zeros after the decimal point, without going below `minScale`
+func (d *Decimal) trim(minScale int) *Decimal {
+	unscaled, scale := new(big.Int).Set(d.Unscaled), d.Scale
+	rem := new(big.Int)
+
+	for scale > minScale {
+		quo, r := new(big.Int).QuoRem(unscaled, bigTen, rem)
+		if r.Sign() != 0 {
+			break
+		}
+
+		unscaled, scale = quo, scale-1
+	}
+
+	return NewDecimal(unscaled, scale)
+}
+
+func (d *Decimal) String() string {
+	digits := new(big.Int).Abs(d.Unscaled).String()
+
+	sign := ""
+	if d.Unscaled.Sign() < 0 {
+		sign = "-"
+	}
+
+	if d.Scale == 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= d.Scale {
+		digits = strings.Repeat("0", d.Scale-len(digits)+1) + digits
+	}
+
+	return sign + digits[:len(digits)-d.Scale] + "." + digits[len(digits)-d.Scale:]
+}
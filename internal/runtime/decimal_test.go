@@ -0,0 +1,94 @@
+package runtime
+
+import "testing"
+
+func decimal(t *testing.T, s string) *Decimal {
+	t.Helper()
+
+	d, err := ParseDecimal(s)
+	if err != nil {
+		t.Fatalf("parsing %q: %s", s, err.Error())
+	}
+
+	return d
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	tests := []struct {
+		left     string
+		operator string
+		right    string
+		expected string
+	}{
+		{"0.1", "+", "0.2", "0.3"},
+		{"1.10", "+", "2", "3.10"},
+		{"1", "-", "0.01", "0.99"},
+		{"1.10", "*", "3", "3.30"},
+		{"-0.5", "*", "0.5", "-0.25"},
+		{"1", "/", "3", "0.3333333333333333"},
+		{"2", "/", "3", "0.6666666666666667"},
+		{"-2", "/", "3", "-0.6666666666666667"},
+		{"10.00", "/", "4", "2.50"},
+		{"1", "/", "8", "0.125"},
+		{"-7.5", "%", "2", "-1.5"},
+		{"7.5", "%", "-2", "1.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.left+tt.operator+tt.right, func(t *testing.T) {
+			left, right := decimal(t, tt.left), decimal(t, tt.right)
+
+			var got *Decimal
+			switch tt.operator {
+			case "+":
+				got = left.Add(right)
+			case "-":
+				got = left.Sub(right)
+			case "*":
+				got = left.Mul(right)
+			case "/":
+				got = left.Quo(right)
+			case "%":
+				got = left.Rem(right)
+			}
+
+			if got.String() != tt.expected {
+				t.Errorf("got %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecimalCmp(t *testing.T) {
+	if decimal(t, "1.10").Cmp(decimal(t, "1.1")) != 0 {
+		t.Errorf("expected 1.10 to equal 1.1")
+	}
+
+	if decimal(t, "-0.01").Cmp(decimal(t, "0")) >= 0 {
+		t.Errorf("expected -0.01 to be less than 0")
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{"1.10", "1.10"},
+		{"-0.05", "-0.05"},
+		{".5", "0.5"},
+		{"42", "42"},
+	}
+
+	for _, tt := range tests {
+		if got := decimal(t, tt.src).String(); got != tt.expected {
+			t.Errorf("%q: got %s, expected %s", tt.src, got, tt.expected)
+		}
+	}
+
+	for _, src := range []string{"", "abc", "1.2.3", "1.-5"} {
+		if _, err := ParseDecimal(src); err == nil {
+			t.Errorf("%q: expected an error", src)
+		}
+	}
+}
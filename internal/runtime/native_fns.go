@@ -1,7 +1,285 @@
 package runtime
 
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// NativeFn is a function implemented in go which can be called from brtlang code. its result is
+// passed through FromGo, so it may hold any go integer or float type besides the types brtlang uses
+type NativeFn func(args []RuntimeValue) (RuntimeValue, error)
+
+type NativeFnMapping struct {
+	Name string
+	// number of arguments the function expects, a negative arity accepts any number of arguments
+	Arity int
+	Fn    NativeFn
+}
+
+type RuntimeNativeFnMapping = map[string]NativeFnMapping
+
 var (
 	VibeCheck = "vibeCheck"
+	Len       = "len"
+	Push      = "push"
+	Pop       = "pop"
+	Slice     = "slice"
+	Has       = "has"
+	Delete    = "delete"
+	Keys      = "keys"
+	Values    = "values"
+	BigInt    = "bigint"
+	ToDecimal = "decimal"
 )
 
-var NativeFns = []string{VibeCheck}
+// native functions registered in every runtime
+var defaultNativeFns = []NativeFnMapping{
+	{Name: VibeCheck, Arity: 0, Fn: vibeCheck},
+	{Name: Len, Arity: 1, Fn: length},
+	{Name: Push, Arity: 2, Fn: push},
+	{Name: Pop, Arity: 1, Fn: pop},
+	{Name: Slice, Arity: -1, Fn: slice},
+	{Name: Has, Arity: 2, Fn: has},
+	{Name: Delete, Arity: 2, Fn: deleteKey},
+	{Name: Keys, Arity: 1, Fn: keys},
+	{Name: Values, Arity: 1, Fn: values},
+	{Name: BigInt, Arity: 1, Fn: bigInt},
+	{Name: ToDecimal, Arity: 1, Fn: toDecimal},
+}
+
+// vibeCheck() -> current unix timestamp in seconds
+func vibeCheck(args []RuntimeValue) (RuntimeValue, error) {
+	return *NewRuntimeValue(time.Now().Unix()), nil
+}
+
+// len(list | map | string) -> number of elements, entries or characters
+func length(args []RuntimeValue) (RuntimeValue, error) {
+	switch v := args[0].Value.(type) {
+	case *List:
+		return *NewRuntimeValue(int64(len(v.Elements))), nil
+	case *Map:
+		return *NewRuntimeValue(int64(len(v.Keys))), nil
+	case string:
+		return *NewRuntimeValue(int64(utf8.RuneCountInString(v))), nil
+	default:
+		return RuntimeValue{}, InvalidArgumentErrBuilder(Len, "a list, map or string")
+	}
+}
+
+// push(list, value) -> appends value to the end of list
+func push(args []RuntimeValue) (RuntimeValue, error) {
+	list, ok := args[0].Value.(*List)
+	if !ok {
+		return RuntimeValue{}, InvalidArgumentErrBuilder(Push, "a list")
+	}
+
+	list.Elements = append(list.Elements, args[1])
+	return *NewRuntimeValue(nil), nil
+}
+
+// pop(list) -> removes and returns the last element of list
+func pop(args []RuntimeValue) (RuntimeValue, error) {
+	list, ok := args[0].Value.(*List)
+	if !ok {
+		return RuntimeValue{}, InvalidArgumentErrBuilder(Pop, "a list")
+	}
+
+	if len(list.Elements) == 0 {
+		return RuntimeValue{}, EMPTY_LIST
+	}
+
+	last := list.Elements[len(list.Elements)-1]
+	list.Elements = list.Elements[:len(list.Elements)-1]
+	return last, nil
+}
+
+// slice(list, start[, end]) -> new list with the elements in [start, end)
+func slice(args []RuntimeValue) (RuntimeValue, error) {
+	if len(args) != 2 && len(args) != 3 {
+		return RuntimeValue{}, InvalidArgumentErrBuilder(Slice, "a list, a start index and an optional end index")
+	}
+
+	list, ok := args[0].Value.(*List)
+	if !ok {
+		return RuntimeValue{}, InvalidArgumentErrBuilder(Slice, "a list")
+	}
+
+	start, err := ToIndex(args[1], len(list.Elements)+1)
+	if err != nil {
+		return RuntimeValue{}, err
+	}
+
+	end := len(list.Elements)
+	if len(args) == 3 {
+		end, err = ToIndex(args[2], len(list.Elements)+1)
+		if err != nil {
+			return RuntimeValue{}, err
+		}
+	}
+
+	if start > end {
+		return RuntimeValue{}, INDEX_OUT_OF_RANGE
+	}
+
+	elements := make([]RuntimeValue, end-start)
+	copy(elements, list.Elements[start:end])
+	return *NewRuntimeValue(NewList(elements)), nil
+}
+
+// has(map, key) -> whether key exists in map
+func has(args []RuntimeValue) (RuntimeValue, error) {
+	m, ok := args[0].Value.(*Map)
+	if !ok {
+		return RuntimeValue{}, InvalidArgumentErrBuilder(Has, "a map")
+	}
+
+	_, exists := m.Get(args[1])
+	return *NewRuntimeValue(exists), nil
+}
+
+// delete(map, key) -> removes key from map, returns whether it existed
+func deleteKey(args []RuntimeValue) (RuntimeValue, error) {
+	m, ok := args[0].Value.(*Map)
+	if !ok {
+		return RuntimeValue{}, InvalidArgumentErrBuilder(Delete, "a map")
+	}
+
+	return *NewRuntimeValue(m.Delete(args[1])), nil
+}
+
+// keys(map) -> list of keys in insertion order
+func keys(args []RuntimeValue) (RuntimeValue, error) {
+	m, ok := args[0].Value.(*Map)
+	if !ok {
+		return RuntimeValue{}, InvalidArgumentErrBuilder(Keys, "a map")
+	}
+
+	elements := make([]RuntimeValue, len(m.Keys))
+	copy(elements, m.Keys)
+	return *NewRuntimeValue(NewList(elements)), nil
+}
+
+// values(map) -> list of values in insertion order of their keys
+func values(args []RuntimeValue) (RuntimeValue, error) {
+	m, ok := args[0].Value.(*Map)
+	if !ok {
+		return RuntimeValue{}, InvalidArgumentErrBuilder(Values, "a map")
+	}
+
+	elements := make([]RuntimeValue, len(m.Keys))
+	for i, key := range m.Keys {
+		elements[i], _ = m.Get(key)
+	}
+
+	return *NewRuntimeValue(NewList(elements)), nil
+}
+
+// bigint(int | float | bigint | decimal | string) -> the number as a big int, floats and decimals
+// lose their fraction
+func bigInt(args []RuntimeValue) (RuntimeValue, error) {
+	switch v := args[0].Value.(type) {
+	case int64:
+		return *NewRuntimeValue(big.NewInt(v)), nil
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return RuntimeValue{}, INVALID_BIG_INT
+		}
+
+		num, _ := big.NewFloat(v).Int(nil)
+		return *NewRuntimeValue(num), nil
+	case *big.Int:
+		return args[0], nil
+	case *Decimal:
+		return *NewRuntimeValue(new(big.Int).Quo(v.Unscaled, pow10(v.Scale))), nil
+	case string:
+		num, ok := new(big.Int).SetString(v, 10)
+		if !ok {
+			return RuntimeValue{}, INVALID_BIG_INT
+		}
+
+		return *NewRuntimeValue(num), nil
+	default:
+		return RuntimeValue{}, InvalidArgumentErrBuilder(BigInt, "a number or a string")
+	}
+}
+
+// decimal(int | float | bigint | decimal | string) -> the number as a decimal. floats are converted
+// from the shortest form they print as, so decimal(0.1) is exactly 0.1
+func toDecimal(args []RuntimeValue) (RuntimeValue, error) {
+	switch v := args[0].Value.(type) {
+	case int64:
+		return *NewRuntimeValue(NewDecimal(big.NewInt(v), 0)), nil
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return RuntimeValue{}, INVALID_DECIMAL
+		}
+
+		num, err := ParseDecimal(strconv.FormatFloat(v, 'f', -1, 64))
+		return *NewRuntimeValue(num), err
+	case *big.Int:
+		return *NewRuntimeValue(NewDecimal(v, 0)), nil
+	case *Decimal:
+		return args[0], nil
+	case string:
+		num, err := ParseDecimal(v)
+		return *NewRuntimeValue(num), err
+	default:
+		return RuntimeValue{}, InvalidArgumentErrBuilder(ToDecimal, "a number or a string")
+	}
+}
+
+// converts `index` into an int in [0, size), erroring on negative, fractional and out of range indices.
+// whole floats can be used as indices too
+func ToIndex(index RuntimeValue, size int) (int, error) {
+	num, ok := ToFloat(index)
+	if !ok || num != math.Trunc(num) {
+		return 0, INVALID_INDEX
+	}
+
+	if num < 0 {
+		return 0, NEGATIVE_INDEX
+	}
+
+	if num >= float64(size) {
+		return 0, INDEX_OUT_OF_RANGE
+	}
+
+	return int(num), nil
+}
+
+// FromGo wraps a go value for brtlang code. go integer types are converted to ints and go float types
+// to floats, other values are used as they are
+func FromGo(value interface{}) RuntimeValue {
+	switch v := value.(type) {
+	case int:
+		return *NewRuntimeValue(int64(v))
+	case int8:
+		return *NewRuntimeValue(int64(v))
+	case int16:
+		return *NewRuntimeValue(int64(v))
+	case int32:
+		return *NewRuntimeValue(int64(v))
+	case uint:
+		return FromGo(uint64(v))
+	case uint8:
+		return *NewRuntimeValue(int64(v))
+	case uint16:
+		return *NewRuntimeValue(int64(v))
+	case uint32:
+		return *NewRuntimeValue(int64(v))
+	case uint64:
+		// too large for an int, so it becomes the closest float
+		if v > math.MaxInt64 {
+			return *NewRuntimeValue(float64(v))
+		}
+
+		return *NewRuntimeValue(int64(v))
+	case float32:
+		return *NewRuntimeValue(float64(v))
+	default:
+		return *NewRuntimeValue(v)
+	}
+}
@@ -2,27 +2,182 @@ package runtime
 
 import (
 	"fmt"
+	"io"
 	"math"
+	"math/big"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/0xmukesh/interpreter/internal/ast"
 )
 
+// how deep function calls can nest before STACK_OVERFLOW is reported, counting the top level as one.
+// both the tree-walking runner and the vm stop at it. the runner uses the go stack for every call, so
+// it's kept well below the depth which would exceed go's stack limit and crash the process
+const MAX_CALL_DEPTH = 10000
+
 type RuntimeValue struct {
 	Value interface{}
 }
 
 type RuntimeVarMapping = map[string]RuntimeValue
-type FuncMapping struct {
-	Node ast.AstNode
-	Args []ast.AstNode
+
+// Function is the value of a `skibidi` declaration or expression. it closes over the
+// environment it was created in, so its body can keep using that environment's variables
+type Function struct {
+	Name    string
+	Node    ast.AstNode
+	Args    []ast.AstNode
+	Closure *Environment
+}
+
+func NewFunction(name string, args []ast.AstNode, node ast.AstNode, closure *Environment) *Function {
+	return &Function{
+		Name:    name,
+		Node:    node,
+		Args:    args,
+		Closure: closure,
+	}
+}
+func (f Function) String() string {
+	if f.Name == "" {
+		return "<skibidi>"
+	}
+
+	return fmt.Sprintf("<skibidi %s>", f.Name)
+}
+
+// List is the value of a list literal. lists are mutable and shared by reference
+type List struct {
+	Elements []RuntimeValue
+}
+
+func NewList(elements []RuntimeValue) *List {
+	return &List{
+		Elements: elements,
+	}
+}
+func (l *List) String() string {
+	return l.format(nil)
+}
+
+// `enclosing` holds the collections being formatted which contain this list, a list which contains itself
+// is printed as [...] where it shows up again
+func (l *List) format(enclosing map[any]bool) string {
+	if enclosing[l] {
+		return "[...]"
+	}
+
+	if enclosing == nil {
+		enclosing = make(map[any]bool)
+	}
+
+	enclosing[l] = true
+	defer delete(enclosing, l)
+
+	elements := make([]string, len(l.Elements))
+	for i, element := range l.Elements {
+		elements[i] = element.format(true, enclosing)
+	}
+
+	return fmt.Sprintf("[%s]", strings.Join(elements, ", "))
+}
+
+// Map is the value of a map literal. keys are strings or numbers and are kept in insertion order.
+// whole floats are the same key as the int they are equal to. maps are mutable and shared by reference
+type Map struct {
+	Keys    []RuntimeValue
+	Entries map[interface{}]RuntimeValue
+}
+
+func NewMap() *Map {
+	return &Map{
+		Entries: make(map[interface{}]RuntimeValue),
+	}
+}
+
+// checks that `key` can be used as a map key
+func ValidateMapKey(key RuntimeValue) error {
+	switch key.Value.(type) {
+	case string, int64, float64, *big.Int, *Decimal:
+		return nil
+	default:
+		return INVALID_MAP_KEY
+	}
+}
+
+// big ints and decimals are stored by their value rather than by their pointer, decimals without
+// their trailing zeros so 1.1d and 1.10d are the same key
+type bigIntKey string
+type decimalKey string
+
+// returns what `key` is stored under in `Entries`
+func mapKey(key RuntimeValue) interface{} {
+	switch v := key.Value.(type) {
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			return int64(v)
+		}
+	case *big.Int:
+		return bigIntKey(v.String())
+	case *Decimal:
+		return decimalKey(v.trim(0).String())
+	}
+
+	return key.Value
+}
+func (m *Map) Get(key RuntimeValue) (RuntimeValue, bool) {
+	val, ok := m.Entries[mapKey(key)]
+	return val, ok
+}
+func (m *Map) Set(key RuntimeValue, value RuntimeValue) {
+	if _, ok := m.Entries[mapKey(key)]; !ok {
+		m.Keys = append(m.Keys, key)
+	}
+
+	m.Entries[mapKey(key)] = value
 }
-type RuntimeFuncMapping = map[string]FuncMapping
+func (m *Map) Delete(key RuntimeValue) bool {
+	if _, ok := m.Entries[mapKey(key)]; !ok {
+		return false
+	}
 
-func NewFuncMapping(node ast.AstNode, args []ast.AstNode) FuncMapping {
-	return FuncMapping{
-		Node: node,
-		Args: args,
+	delete(m.Entries, mapKey(key))
+	for i, k := range m.Keys {
+		if mapKey(k) == mapKey(key) {
+			m.Keys = append(m.Keys[:i], m.Keys[i+1:]...)
+			break
+		}
 	}
+
+	return true
+}
+func (m *Map) String() string {
+	return m.format(nil)
+}
+
+// like List.format, a map which contains itself is printed as {...} where it shows up again
+func (m *Map) format(enclosing map[any]bool) string {
+	if enclosing[m] {
+		return "{...}"
+	}
+
+	if enclosing == nil {
+		enclosing = make(map[any]bool)
+	}
+
+	enclosing[m] = true
+	defer delete(enclosing, m)
+
+	entries := make([]string, len(m.Keys))
+	for i, key := range m.Keys {
+		val, _ := m.Get(key)
+		entries[i] = fmt.Sprintf("%s: %s", key.Repr(), val.format(true, enclosing))
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(entries, ", "))
 }
 
 func NewRuntimeValue(value interface{}) *RuntimeValue {
@@ -31,78 +186,376 @@ func NewRuntimeValue(value interface{}) *RuntimeValue {
 	}
 }
 func (e RuntimeValue) String() string {
+	return e.format(false, nil)
+}
+
+// like String, but quotes strings so they can be told apart inside collections
+func (e RuntimeValue) Repr() string {
+	return e.format(true, nil)
+}
+
+// formats the value, quoting it if it's a string and `quote` is set. `enclosing` holds the
+// collections being formatted which contain the value
+func (e RuntimeValue) format(quote bool, enclosing map[any]bool) string {
 	switch v := e.Value.(type) {
 	case string:
+		if quote {
+			return strconv.Quote(v)
+		}
+
 		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return formatFloat(v)
+	case *big.Int:
+		return v.String()
+	case *Decimal:
+		return v.String()
+	case *List:
+		return v.format(enclosing)
+	case *Map:
+		return v.format(enclosing)
+	case *Function:
+		return v.String()
+	case *NativeFnMapping:
+		return fmt.Sprintf("<native skibidi %s>", v.Name)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// formats a float in the shortest form which reads back as the same float. whole floats keep their
+// ".0" so they can be told apart from ints, and very small or large floats use an exponent
+func formatFloat(v float64) string {
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+
+	format := byte('f')
+	if abs := math.Abs(v); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+
+	s := strconv.FormatFloat(v, format, -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+
+	return s
+}
+
+// lists or maps which are being compared with each other, see equals
+type comparison struct {
+	left  any
+	right any
+}
+
+// reports whether both values are equal. lists and maps are compared element by element
+func (e RuntimeValue) Equals(other RuntimeValue) bool {
+	return e.equals(other, nil)
+}
+
+// `comparing` holds the pairs of collections whose comparison is in progress. collections which
+// contain themselves would be compared forever, so a pair which comes up again is taken to be equal
+// and the rest of the elements decide
+func (e RuntimeValue) equals(other RuntimeValue, comparing map[comparison]bool) bool {
+	switch v := e.Value.(type) {
+	case *List:
+		otherList, ok := other.Value.(*List)
+		if !ok || len(v.Elements) != len(otherList.Elements) {
+			return false
+		}
+
+		pair := comparison{v, otherList}
+		if comparing[pair] {
+			return true
+		}
+
+		if comparing == nil {
+			comparing = make(map[comparison]bool)
+		}
+
+		comparing[pair] = true
+		defer delete(comparing, pair)
+
+		for i := range v.Elements {
+			if !v.Elements[i].equals(otherList.Elements[i], comparing) {
+				return false
+			}
+		}
+
+		return true
+	case *Map:
+		otherMap, ok := other.Value.(*Map)
+		if !ok || len(v.Keys) != len(otherMap.Keys) {
+			return false
+		}
+
+		pair := comparison{v, otherMap}
+		if comparing[pair] {
+			return true
+		}
+
+		if comparing == nil {
+			comparing = make(map[comparison]bool)
+		}
+
+		comparing[pair] = true
+		defer delete(comparing, pair)
+
+		for _, key := range v.Keys {
+			val, _ := v.Get(key)
+			otherVal, ok := otherMap.Get(key)
+			if !ok || !val.equals(otherVal, comparing) {
+				return false
+			}
+		}
+
+		return true
+	case int64:
+		switch otherNum := other.Value.(type) {
+		case int64:
+			return v == otherNum
+		case float64:
+			return float64(v) == otherNum
+		default:
+			return false
+		}
+	case float64:
+		otherNum, ok := ToFloat(other)
+		return ok && v == otherNum
+	case *big.Int:
+		otherNum, ok := other.Value.(*big.Int)
+		return ok && v.Cmp(otherNum) == 0
+	case *Decimal:
+		otherNum, ok := other.Value.(*Decimal)
+		return ok && v.Cmp(otherNum) == 0
+	default:
+		return e.Value == other.Value
+	}
+}
+
+// returns the name of the value's type, as used in error messages
+func (e RuntimeValue) TypeName() string {
+	switch e.Value.(type) {
+	case nil:
+		return "nada"
+	case bool:
+		return "bool"
+	case int64:
+		return "int"
 	case float64:
-		if v == math.Floor(v) {
-			return fmt.Sprintf("%d", int64(v))
+		return "float"
+	case *big.Int:
+		return "bigint"
+	case *Decimal:
+		return "decimal"
+	case string:
+		return "string"
+	case *List:
+		return "list"
+	case *Map:
+		return "map"
+	default:
+		return "skibidi"
+	}
+}
+
+// reports whether the value is an int, float, big int or decimal
+func IsNumber(val RuntimeValue) bool {
+	switch val.Value.(type) {
+	case int64, float64, *big.Int, *Decimal:
+		return true
+	default:
+		return false
+	}
+}
+
+// reports whether both values are of the same type, ints and floats are both numbers
+func SameType(a RuntimeValue, b RuntimeValue) bool {
+	if _, isNum := ToFloat(a); isNum {
+		_, isOtherNum := ToFloat(b)
+		return isOtherNum
+	}
+
+	return reflect.TypeOf(a.Value) == reflect.TypeOf(b.Value)
+}
+
+// returns the value of an int or a float as a float, reports false if `val` isn't a number
+func ToFloat(val RuntimeValue) (float64, bool) {
+	switch v := val.Value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// ParseNumber returns the value of a number literal. literals ending with "n" are big ints and
+// literals ending with "d" are decimals, otherwise literals with a decimal point or an exponent are
+// floats and the rest are ints
+func ParseNumber(literal string) (RuntimeValue, error) {
+	if digits, ok := strings.CutSuffix(literal, "n"); ok {
+		num, ok := new(big.Int).SetString(digits, 10)
+		if !ok {
+			return RuntimeValue{}, INVALID_BIG_INT
 		}
 
-		return fmt.Sprintf("%f", v)
+		return *NewRuntimeValue(num), nil
+	}
+
+	if digits, ok := strings.CutSuffix(literal, "d"); ok {
+		num, err := ParseDecimal(digits)
+		return *NewRuntimeValue(num), err
+	}
+
+	if strings.ContainsAny(literal, ".e") {
+		num, err := strconv.ParseFloat(literal, 64)
+		return *NewRuntimeValue(num), err
+	}
+
+	num, err := strconv.ParseInt(literal, 10, 64)
+	return *NewRuntimeValue(num), err
+}
+
+// reports whether the value counts as true in truthy mode. nada, cap, 0, "" and empty lists and maps
+// are falsy, everything else is truthy
+func IsTruthy(val RuntimeValue) bool {
+	switch v := val.Value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case int64:
+		return v != 0
+	case float64:
+		return v != 0
+	case *big.Int:
+		return v.Sign() != 0
+	case *Decimal:
+		return v.Sign() != 0
+	case string:
+		return v != ""
+	case *List:
+		return len(v.Elements) != 0
+	case *Map:
+		return len(v.Keys) != 0
 	default:
-		return fmt.Sprintf("%v", v)
+		return true
 	}
 }
 
+// Environment holds the variables of a scope. globals are kept by name in `Vars`, every other
+// variable is kept in `Slots` at the index the resolver gave it
 type Environment struct {
 	Parent *Environment
 	Vars   RuntimeVarMapping
-	Funcs  RuntimeFuncMapping
+	// nil until the variable in the slot is declared
+	Slots []*RuntimeValue
 }
 
-func NewEnvironment(vars RuntimeVarMapping, funcs RuntimeFuncMapping, parent *Environment) *Environment {
+func NewEnvironment(vars RuntimeVarMapping, parent *Environment) *Environment {
 	return &Environment{
 		Parent: parent,
 		Vars:   vars,
-		Funcs:  funcs,
 	}
 }
 
-func (e *Environment) GetVar(name string) (*RuntimeValue, *Environment) {
-	val, ok := e.Vars[name]
-	if !ok {
-		if e.Parent == nil {
-			return nil, nil
-		}
+func (e *Environment) SetVar(name string, value RuntimeValue) {
+	e.Vars[name] = value
+}
 
-		return e.Parent.GetVar(name)
+// returns the environment `depth` levels up the parent chain
+func (e *Environment) Ancestor(depth int) *Environment {
+	env := e
+	for i := 0; i < depth; i++ {
+		env = env.Parent
 	}
 
-	return &val, e
+	return env
 }
-func (e *Environment) GetFunc(name string) (*FuncMapping, *Environment) {
-	val, ok := e.Funcs[name]
-	if !ok {
-		if e.Parent == nil {
-			return nil, nil
-		}
 
-		return e.Parent.GetFunc(name)
+// returns the value of the variable in `slot` of the environment `depth` levels up, nil if it isn't
+// declared yet
+func (e *Environment) GetAt(depth int, slot int) *RuntimeValue {
+	env := e.Ancestor(depth)
+	if slot >= len(env.Slots) {
+		return nil
 	}
 
-	return &val, e
+	return env.Slots[slot]
 }
-func (e *Environment) SetVar(name string, value RuntimeValue) {
-	e.Vars[name] = value
+
+// declares the variable in `slot`, reports false if it's already declared
+func (e *Environment) DefineAt(slot int, value RuntimeValue) bool {
+	for len(e.Slots) <= slot {
+		e.Slots = append(e.Slots, nil)
+	}
+
+	if e.Slots[slot] != nil {
+		return false
+	}
+
+	e.Slots[slot] = &value
+	return true
 }
-func (e *Environment) SetFunc(name string, node ast.AstNode, args []ast.AstNode) {
-	e.Funcs[name] = FuncMapping{
-		Node: node,
-		Args: args,
+
+// assigns to the variable in `slot` of the environment `depth` levels up, reports false if it isn't
+// declared yet
+func (e *Environment) SetAt(depth int, slot int, value RuntimeValue) bool {
+	val := e.GetAt(depth, slot)
+	if val == nil {
+		return false
 	}
+
+	*val = value
+	return true
 }
 
 type Runtime struct {
-	Envs *[]Environment
+	Envs      *[]*Environment
+	NativeFns RuntimeNativeFnMapping
+	Stdout    io.Writer
+	Stderr    io.Writer
+	// when set, `&&`, `||` and `!` accept any value and `&&`/`||` return one of their operands
+	TruthyLogic bool
 }
 
-func NewRuntime(envs *[]Environment) *Runtime {
-	return &Runtime{
-		Envs: envs,
+func NewRuntime(envs *[]*Environment) *Runtime {
+	r := &Runtime{
+		Envs:      envs,
+		NativeFns: make(RuntimeNativeFnMapping),
+		Stdout:    os.Stdout,
+		Stderr:    os.Stderr,
+	}
+
+	for _, nativeFn := range defaultNativeFns {
+		r.RegisterNativeFn(nativeFn.Name, nativeFn.Arity, nativeFn.Fn)
 	}
+
+	return r
 }
-func (r *Runtime) AddNewEnv(env Environment) {
+
+// registers (or replaces) a native function callable by `name`
+func (r *Runtime) RegisterNativeFn(name string, arity int, fn NativeFn) {
+	r.NativeFns[name] = NativeFnMapping{
+		Name:  name,
+		Arity: arity,
+		Fn:    fn,
+	}
+}
+func (r *Runtime) GetNativeFn(name string) (*NativeFnMapping, bool) {
+	nativeFn, ok := r.NativeFns[name]
+	if !ok {
+		return nil, false
+	}
+
+	return &nativeFn, true
+}
+func (r *Runtime) AddNewEnv(env *Environment) {
 	if r.Envs != nil {
 		*r.Envs = append(*r.Envs, env)
 	}
@@ -112,9 +565,22 @@ func (r *Runtime) RemoveLastEnv() {
 		*r.Envs = (*r.Envs)[:len(*r.Envs)-1]
 	}
 }
+
+// drops every environment except the global one, used to recover from errors raised mid-block
+func (r *Runtime) ResetToGlobalEnv() {
+	if r.Envs != nil && len(*r.Envs) > 1 {
+		*r.Envs = (*r.Envs)[:1]
+	}
+}
+func (r *Runtime) GlobalEnv() *Environment {
+	if r.Envs != nil {
+		return (*r.Envs)[0]
+	}
+	return nil
+}
 func (r *Runtime) CurrEnv() *Environment {
 	if r.Envs != nil {
-		return &(*r.Envs)[len(*r.Envs)-1]
+		return (*r.Envs)[len(*r.Envs)-1]
 	}
 	return nil
 }
@@ -1,47 +1,124 @@
 package runtime
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/0xmukesh/interpreter/internal/diagnostics"
+	"github.com/0xmukesh/interpreter/internal/tokens"
 )
 
 type RuntimeError struct {
+	Code    string
 	Message string
 	At      string
-	Line    int
+	Span    tokens.Span
+	Notes   []diagnostics.Note
+	// name of the file being run, it's empty when the source doesn't come from a file
+	File string
 }
 
-const (
-	UNDEFINED_IDENTIFIER      = "damn bruv, this identifier got that invisible drip"
-	IDENTIFIER_ALREADY_EXISTS = "nah, the sequel ain't happening for this identifier"
+var (
+	UNDEFINED_IDENTIFIER      = diagnostics.Message{Code: "E001", Text: "damn bruv, this identifier got that invisible drip"}
+	IDENTIFIER_ALREADY_EXISTS = diagnostics.Message{Code: "E002", Text: "nah, the sequel ain't happening for this identifier"}
+	NOT_CALLABLE              = diagnostics.Message{Code: "E003", Text: "bruh, you can't call that. it ain't a skibidi"}
+	NOT_INDEXABLE             = diagnostics.Message{Code: "E004", Text: "bruh, you can't index that. it ain't a list or map"}
+	INVALID_MAP_KEY           = diagnostics.Message{Code: "E005", Text: "that key ain't it, chief. map keys need to be a string or number"}
+	NEGATIVE_INDEX            = diagnostics.Message{Code: "E006", Text: "negative index? we don't count backwards around here"}
+	INDEX_OUT_OF_RANGE        = diagnostics.Message{Code: "E007", Text: "that index is outta range, fam"}
+	INVALID_INDEX             = diagnostics.Message{Code: "E008", Text: "that index ain't it, chief. it needs to be a whole number"}
+	EMPTY_LIST                = diagnostics.Message{Code: "E009", Text: "bro tried to pop from an empty list"}
+	STACK_OVERFLOW            = diagnostics.Message{Code: "E010", Text: "bro's recursion went way too deep, the stack overflowed"}
+	DIVIDE_BY_ZERO            = diagnostics.Message{Code: "E011", Text: "ya buddy did you really graduate high school? you can't divide by 0, bruh"}
+	INVALID_BIG_INT           = diagnostics.Message{Code: "E018", Text: "that big int ain't it, chief. it needs to be a whole number"}
+	INVALID_DECIMAL           = diagnostics.Message{Code: "E019", Text: "that decimal ain't it, chief. it needs to be digits with an optional decimal point"}
+	NEGATIVE_SHIFT            = diagnostics.Message{Code: "E021", Text: "bro tried to shift by a negative amount, that ain't how bits work"}
+	NEGATIVE_EXPONENT         = diagnostics.Message{Code: "E022", Text: "negative exponents? ints can't do fractions, fam"}
+	BIG_INT_TOO_LARGE         = diagnostics.Message{Code: "E023", Text: "that big int would be way too big, fam. '<<' and '**' can't make ints with millions of bits"}
+
+	INVALID_OPERAND_TEMPLATE = diagnostics.Message{Code: "E012", Text: "this operand ain't it, chief. got %s"}
+	INVALID_OPERATOR         = diagnostics.Message{Code: "E013", Text: "this operator ain't it, chief"}
 
-	INVALID_OPERAND_TEMPLATE = "this operand ain't it, chief. got %s"
-	INVALID_OPERATOR         = "this operator ain't it, chief"
+	OPERANDS_MUST_BE_SAME            = diagnostics.Message{Code: "E014", Text: "the operands out here are wildin'. they need to be of the same type"}
+	OPERANDS_MUST_BE_OF_TEMPLATE     = diagnostics.Message{Code: "E014", Text: "the operands out here are wildin'. they need to be of type %s"}
+	OPERANDS_MUST_BE_EITHER_TEMPLATE = diagnostics.Message{Code: "E014", Text: "the operands out here are wildin'. they need to be of type %s or %s"}
+	EXPECTED_EXPR_TEMPLATE           = diagnostics.Message{Code: "E015", Text: "yo, where's the vibe? i was an expecting %s"}
+	ARGUMENTS_COUNT_TEMPLATE         = diagnostics.Message{Code: "E016", Text: "damn, do you even know how you count? the function expected %d arguments but you gave %d arguments"}
+	INVALID_ARGUMENT_TEMPLATE        = diagnostics.Message{Code: "E017", Text: "%s ain't vibing with that argument. expected %s"}
+	MIXED_NUMBERS_TEMPLATE           = diagnostics.Message{Code: "E020", Text: "the numbers out here are wildin'. can't mix %s and %s without converting one of them"}
 )
 
+const CONVERT_NUMBERS_NOTE = "convert with bigint(...) or decimal(...) so both sides are of the same type"
+
+func (e RuntimeError) label() string {
+	return fmt.Sprintf("hell naw, im done you caused a runtime error at '%s'", e.At)
+}
+
 func (e RuntimeError) Error() string {
-	return fmt.Sprintf("[line %d] hell naw, im done you caused a runtime error at '%s': %s", e.Line, e.At, e.Message)
+	return fmt.Sprintf("[%s] %s: %s", e.Span.Start.Format(e.File), e.label(), e.Message)
 }
 
-func NewRuntimeError(msg string, at string, line int) *RuntimeError {
+func (e RuntimeError) Diagnostic() diagnostics.Diagnostic {
+	return diagnostics.Diagnostic{
+		Severity: diagnostics.ERROR,
+		Code:     e.Code,
+		Message:  e.Message,
+		Label:    e.label(),
+		Span:     e.Span,
+		Notes:    e.Notes,
+	}
+}
+
+func NewRuntimeError(msg diagnostics.Message, at string, span tokens.Span) *RuntimeError {
 	return &RuntimeError{
-		Message: msg,
+		Code:    msg.Code,
+		Message: msg.Text,
 		At:      at,
-		Line:    line,
+		Span:    span,
+	}
+}
+
+// MessageOf returns the message of an error returned by a native function or a helper. errors which
+// aren't one of the messages above, such as the ones of a host's native functions, get "E000"
+func MessageOf(err error) diagnostics.Message {
+	var msg diagnostics.Message
+	if errors.As(err, &msg) {
+		return msg
 	}
+
+	return diagnostics.Message{Code: "E000", Text: err.Error()}
 }
 
-func OperandsMustBeOfErrBuilder(expectedTypes ...string) string {
+// adds a note to the error, `span` is nil if the note doesn't point at the source
+func (e *RuntimeError) WithNote(msg string, span *tokens.Span) *RuntimeError {
+	e.Notes = append(e.Notes, diagnostics.Note{Message: msg, Span: span})
+	return e
+}
+
+func OperandsMustBeOfErrBuilder(expectedTypes ...string) diagnostics.Message {
 	if len(expectedTypes) == 1 {
 		if expectedTypes[0] == "same" {
-			return "the operands out here are wildin'. they need to be of the same type"
+			return OPERANDS_MUST_BE_SAME
 		}
-		return fmt.Sprintf("the operands out here are wildin'. they need to be of type %s", expectedTypes[0])
+		return OPERANDS_MUST_BE_OF_TEMPLATE.With(expectedTypes[0])
 	} else {
-		return fmt.Sprintf("the operands out here are wildin'. they need to be of type %s or %s", strings.Join(expectedTypes[:len(expectedTypes)-1], ", "), expectedTypes[len(expectedTypes)-1])
+		return OPERANDS_MUST_BE_EITHER_TEMPLATE.With(strings.Join(expectedTypes[:len(expectedTypes)-1], ", "), expectedTypes[len(expectedTypes)-1])
 	}
 }
 
-func ExpectedExprErrBuilder(expectedExprType string) string {
-	return fmt.Sprintf("yo, where's the vibe? i was an expecting %s", expectedExprType)
+func MixedNumbersErrBuilder(left RuntimeValue, right RuntimeValue) diagnostics.Message {
+	return MIXED_NUMBERS_TEMPLATE.With(left.TypeName(), right.TypeName())
+}
+
+func ExpectedExprErrBuilder(expectedExprType string) diagnostics.Message {
+	return EXPECTED_EXPR_TEMPLATE.With(expectedExprType)
+}
+
+func ArgumentsCountErrBuilder(expected int, got int) diagnostics.Message {
+	return ARGUMENTS_COUNT_TEMPLATE.With(expected, got)
+}
+
+func InvalidArgumentErrBuilder(funcName string, expectedType string) diagnostics.Message {
+	return INVALID_ARGUMENT_TEMPLATE.With(funcName, expectedType)
 }
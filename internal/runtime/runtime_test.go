@@ -0,0 +1,100 @@
+package runtime
+
+import "testing"
+
+func TestSelfReferencingListString(t *testing.T) {
+	xs := NewList([]RuntimeValue{{Value: int64(1)}})
+	xs.Elements = append(xs.Elements, RuntimeValue{Value: xs})
+
+	if got := xs.String(); got != "[1, [...]]" {
+		t.Errorf("expected [1, [...]], got %s", got)
+	}
+
+	// a list which shows up twice without containing itself is printed in full both times
+	inner := NewList([]RuntimeValue{{Value: "a"}})
+	shared := NewList([]RuntimeValue{{Value: inner}, {Value: inner}})
+	if got := shared.String(); got != `[["a"], ["a"]]` {
+		t.Errorf(`expected [["a"], ["a"]], got %s`, got)
+	}
+}
+
+func TestSelfReferencingListEquals(t *testing.T) {
+	xs := NewList([]RuntimeValue{{Value: int64(1)}})
+	xs.Elements = append(xs.Elements, RuntimeValue{Value: xs})
+
+	ys := NewList([]RuntimeValue{{Value: int64(1)}})
+	ys.Elements = append(ys.Elements, RuntimeValue{Value: ys})
+
+	zs := NewList([]RuntimeValue{{Value: int64(2)}})
+	zs.Elements = append(zs.Elements, RuntimeValue{Value: zs})
+
+	tests := []struct {
+		name     string
+		left     *List
+		right    *List
+		expected bool
+	}{
+		{"same list", xs, xs, true},
+		{"same shape", xs, ys, true},
+		{"different element", xs, zs, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			left, right := RuntimeValue{Value: tt.left}, RuntimeValue{Value: tt.right}
+			if got := left.Equals(right); got != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSelfReferencingMapString(t *testing.T) {
+	m := NewMap()
+	m.Set(RuntimeValue{Value: "self"}, RuntimeValue{Value: m})
+
+	if got := m.String(); got != `{"self": {...}}` {
+		t.Errorf(`expected {"self": {...}}, got %s`, got)
+	}
+
+	// cycles which go through both a list and a map
+	xs := NewList(nil)
+	n := NewMap()
+	n.Set(RuntimeValue{Value: "xs"}, RuntimeValue{Value: xs})
+	xs.Elements = append(xs.Elements, RuntimeValue{Value: n})
+
+	if got := (RuntimeValue{Value: n}).String(); got != `{"xs": [{...}]}` {
+		t.Errorf(`expected {"xs": [{...}]}, got %s`, got)
+	}
+}
+
+func TestSelfReferencingMapEquals(t *testing.T) {
+	m := NewMap()
+	m.Set(RuntimeValue{Value: "self"}, RuntimeValue{Value: m})
+
+	n := NewMap()
+	n.Set(RuntimeValue{Value: "self"}, RuntimeValue{Value: n})
+
+	o := NewMap()
+	o.Set(RuntimeValue{Value: "other"}, RuntimeValue{Value: o})
+
+	tests := []struct {
+		name     string
+		left     *Map
+		right    *Map
+		expected bool
+	}{
+		{"same map", m, m, true},
+		{"same shape", m, n, true},
+		{"different key", m, o, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			left, right := RuntimeValue{Value: tt.left}, RuntimeValue{Value: tt.right}
+			if got := left.Equals(right); got != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, got)
+			}
+		})
+	}
+}
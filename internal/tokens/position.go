@@ -0,0 +1,30 @@
+package tokens
+
+import "fmt"
+
+// Position is a place in the source, lines and columns start at 1 and columns count bytes
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+// Format returns the position as "file:line:col", or "line:col" if `file` is empty
+func (p Position) Format(file string) string {
+	if file == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+
+	return fmt.Sprintf("%s:%d:%d", file, p.Line, p.Column)
+}
+
+// Span is the part of the source between `Start` and `End`, `End` is exclusive
+type Span struct {
+	Start Position
+	End   Position
+}
+
+// To returns the span from the start of `s` to the end of `other`
+func (s Span) To(other Span) Span {
+	return Span{Start: s.Start, End: other.End}
+}
@@ -11,9 +11,12 @@ const (
 	RIGHT_PAREN
 	LEFT_BRACE
 	RIGHT_BRACE
+	LEFT_BRACKET
+	RIGHT_BRACKET
 
 	COMMA
 	SEMICOLON
+	COLON
 
 	PLUS
 	PLUS_PLUS
@@ -21,9 +24,23 @@ const (
 	MINUS_MINUS
 	DOT
 	STAR
+	STAR_STAR
 	SLASH
 	MODULO
 
+	PLUS_EQUAL
+	MINUS_EQUAL
+	STAR_EQUAL
+	SLASH_EQUAL
+	MODULO_EQUAL
+
+	BIT_AND
+	BIT_OR
+	BIT_XOR
+	BIT_NOT
+	LESS_LESS
+	GREATER_GREATER
+
 	EQUAL
 	EQUAL_EQUAL
 	BANG
@@ -34,6 +51,10 @@ const (
 	GREATER_EQUAL
 
 	STRING
+	// "text${, }text${ and }text" around the expressions of an interpolated string
+	STRING_START
+	STRING_MIDDLE
+	STRING_END
 	NUMBER
 	IDENTIFIER
 
@@ -56,48 +77,68 @@ const (
 
 	FUNC
 	RETURN
+
+	BREAK
+	CONTINUE
 )
 
 var TknLiteralMapping = map[TokenType]string{
-	LEFT_PAREN:    "(",
-	RIGHT_PAREN:   ")",
-	LEFT_BRACE:    "{",
-	RIGHT_BRACE:   "}",
-	COMMA:         ",",
-	SEMICOLON:     ";",
-	PLUS:          "+",
-	PLUS_PLUS:     "++",
-	MINUS:         "-",
-	MINUS_MINUS:   "--",
-	DOT:           ".",
-	STAR:          "*",
-	SLASH:         "/",
-	MODULO:        "%",
-	EQUAL:         "=",
-	EQUAL_EQUAL:   "==",
-	BANG:          "!",
-	BANG_EQUAL:    "!=",
-	LESS:          "<",
-	LESS_EQUAL:    "<=",
-	GREATER:       ">",
-	GREATER_EQUAL: ">=",
-	AND:           "&&",
-	OR:            "||",
+	LEFT_PAREN:      "(",
+	RIGHT_PAREN:     ")",
+	LEFT_BRACE:      "{",
+	RIGHT_BRACE:     "}",
+	LEFT_BRACKET:    "[",
+	RIGHT_BRACKET:   "]",
+	COMMA:           ",",
+	SEMICOLON:       ";",
+	COLON:           ":",
+	PLUS:            "+",
+	PLUS_PLUS:       "++",
+	MINUS:           "-",
+	MINUS_MINUS:     "--",
+	DOT:             ".",
+	STAR:            "*",
+	STAR_STAR:       "**",
+	SLASH:           "/",
+	MODULO:          "%",
+	PLUS_EQUAL:      "+=",
+	MINUS_EQUAL:     "-=",
+	STAR_EQUAL:      "*=",
+	SLASH_EQUAL:     "/=",
+	MODULO_EQUAL:    "%=",
+	BIT_AND:         "&",
+	BIT_OR:          "|",
+	BIT_XOR:         "^",
+	BIT_NOT:         "~",
+	LESS_LESS:       "<<",
+	GREATER_GREATER: ">>",
+	EQUAL:           "=",
+	EQUAL_EQUAL:     "==",
+	BANG:            "!",
+	BANG_EQUAL:      "!=",
+	LESS:            "<",
+	LESS_EQUAL:      "<=",
+	GREATER:         ">",
+	GREATER_EQUAL:   ">=",
+	AND:             "&&",
+	OR:              "||",
 }
 
 var ReservedKeywordsMapping = map[TokenType]string{
-	TRUE:    TRUE.String(),
-	FALSE:   FALSE.String(),
-	NIL:     NIL.String(),
-	IF:      IF.String(),
-	ELSE_IF: ELSE_IF.String(),
-	ELSE:    ELSE.String(),
-	VAR:     VAR.String(),
-	PRINT:   PRINT.String(),
-	WHILE:   WHILE.String(),
-	FOR:     FOR.String(),
-	FUNC:    FUNC.String(),
-	RETURN:  RETURN.String(),
+	TRUE:     TRUE.String(),
+	FALSE:    FALSE.String(),
+	NIL:      NIL.String(),
+	IF:       IF.String(),
+	ELSE_IF:  ELSE_IF.String(),
+	ELSE:     ELSE.String(),
+	VAR:      VAR.String(),
+	PRINT:    PRINT.String(),
+	WHILE:    WHILE.String(),
+	FOR:      FOR.String(),
+	FUNC:     FUNC.String(),
+	RETURN:   RETURN.String(),
+	BREAK:    BREAK.String(),
+	CONTINUE: CONTINUE.String(),
 }
 
 func (t TokenType) IsReserved() bool {
@@ -123,10 +164,16 @@ func (t TokenType) String() string {
 		return "LEFT_BRACE"
 	case RIGHT_BRACE:
 		return "RIGHT_BRACE"
+	case LEFT_BRACKET:
+		return "LEFT_BRACKET"
+	case RIGHT_BRACKET:
+		return "RIGHT_BRACKET"
 	case COMMA:
 		return "COMMA"
 	case SEMICOLON:
 		return "SEMICOLON"
+	case COLON:
+		return "COLON"
 	case PLUS:
 		return "PLUS"
 	case PLUS_PLUS:
@@ -141,8 +188,32 @@ func (t TokenType) String() string {
 		return "STAR"
 	case SLASH:
 		return "SLASH"
+	case STAR_STAR:
+		return "STAR_STAR"
 	case MODULO:
 		return "MODULO"
+	case PLUS_EQUAL:
+		return "PLUS_EQUAL"
+	case MINUS_EQUAL:
+		return "MINUS_EQUAL"
+	case STAR_EQUAL:
+		return "STAR_EQUAL"
+	case SLASH_EQUAL:
+		return "SLASH_EQUAL"
+	case MODULO_EQUAL:
+		return "MODULO_EQUAL"
+	case BIT_AND:
+		return "BIT_AND"
+	case BIT_OR:
+		return "BIT_OR"
+	case BIT_XOR:
+		return "BIT_XOR"
+	case BIT_NOT:
+		return "BIT_NOT"
+	case LESS_LESS:
+		return "LESS_LESS"
+	case GREATER_GREATER:
+		return "GREATER_GREATER"
 	case EQUAL:
 		return "EQUAL"
 	case EQUAL_EQUAL:
@@ -161,6 +232,12 @@ func (t TokenType) String() string {
 		return "GREATER_EQUAL"
 	case STRING:
 		return "STRING"
+	case STRING_START:
+		return "STRING_START"
+	case STRING_MIDDLE:
+		return "STRING_MIDDLE"
+	case STRING_END:
+		return "STRING_END"
 	case NUMBER:
 		return "NUMBER"
 	case IDENTIFIER:
@@ -193,6 +270,10 @@ func (t TokenType) String() string {
 		return "SKIBIDI"
 	case RETURN:
 		return "BUSSIN"
+	case BREAK:
+		return "YEET"
+	case CONTINUE:
+		return "SKRRT"
 	default:
 		return "ILLEGAL"
 	}
@@ -4,14 +4,18 @@ type Token struct {
 	Type    TokenType
 	Lexeme  string
 	Literal string
-	Line    int
+	Span    Span
 }
 
-func NewToken(tokenType TokenType, lexeme string, literal string, line int) *Token {
+func NewToken(tokenType TokenType, lexeme string, literal string, span Span) *Token {
 	return &Token{
 		Type:    tokenType,
 		Lexeme:  lexeme,
 		Literal: literal,
-		Line:    line,
+		Span:    span,
 	}
 }
+
+func (t Token) GetLine() int {
+	return t.Span.Start.Line
+}
@@ -8,7 +8,6 @@ import (
 	"unicode"
 	"unicode/utf8"
 
-	"github.com/0xmukesh/interpreter/internal/runtime"
 	"github.com/0xmukesh/interpreter/internal/tokens"
 )
 
@@ -72,37 +71,11 @@ func IsReservedKeyword(keyword string) bool {
 	return false
 }
 
-func IsNativeFunc(funcName string) bool {
-	for _, v := range runtime.NativeFns {
-		if v == funcName {
-			return true
-		}
-	}
-	return false
-}
-
 func IsNumber(s string) bool {
 	_, err := strconv.ParseFloat(s, 64)
 	return err == nil
 }
 
-func FindLineNumber(charIdx int, src []byte) int {
-	lineNumber := 1
-
-	for i := range src {
-		if i == charIdx {
-			return lineNumber
-		}
-
-		if src[i] == '\n' {
-			lineNumber++
-		}
-
-	}
-
-	return lineNumber
-}
-
 func IsWhitespace(char byte) bool {
 	return char == '\n' || char == ' ' || char == '\t' || char == 0
 }
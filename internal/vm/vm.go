@@ -0,0 +1,512 @@
+package vm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/0xmukesh/interpreter/internal/compiler"
+	"github.com/0xmukesh/interpreter/internal/diagnostics"
+	"github.com/0xmukesh/interpreter/internal/evaluator"
+	"github.com/0xmukesh/interpreter/internal/runtime"
+	"github.com/0xmukesh/interpreter/internal/tokens"
+)
+
+const FRAMES_MAX = runtime.MAX_CALL_DEPTH
+
+// Closure is the runtime value of a compiled function along with the variables it captured
+type Closure struct {
+	Function *compiler.Function
+	Upvalues []*Upvalue
+}
+
+func (c Closure) String() string {
+	return c.Function.String()
+}
+
+// Upvalue is a variable captured by a closure. it refers to the variable's stack slot while the
+// variable is in scope and holds the value itself once the variable goes out of scope
+type Upvalue struct {
+	slot   int
+	isOpen bool
+	closed runtime.RuntimeValue
+	// next open upvalue, open upvalues are sorted by their slot from top of the stack to the bottom
+	next *Upvalue
+}
+
+// returns the value of the variable the upvalue refers to
+func (u *Upvalue) get(stack []runtime.RuntimeValue) runtime.RuntimeValue {
+	if u.isOpen {
+		return stack[u.slot]
+	}
+
+	return u.closed
+}
+
+type frame struct {
+	closure *Closure
+	ip      int
+	// index of the stack slot holding the called function, the function's locals follow it
+	base int
+}
+
+// VM runs compiled programs. globals live in the runtime's global environment, so they are shared
+// with the tree-walking runner and the host
+type VM struct {
+	Runtime      *runtime.Runtime
+	stack        []runtime.RuntimeValue
+	frames       []frame
+	openUpvalues *Upvalue
+}
+
+func NewVM(rt *runtime.Runtime) *VM {
+	return &VM{
+		Runtime: rt,
+		stack:   make([]runtime.RuntimeValue, 0, 256),
+		frames:  make([]frame, 0, 64),
+	}
+}
+
+func (vm *VM) push(val runtime.RuntimeValue) {
+	vm.stack = append(vm.stack, val)
+}
+
+func (vm *VM) pop() runtime.RuntimeValue {
+	val := vm.stack[len(vm.stack)-1]
+	vm.stack = vm.stack[:len(vm.stack)-1]
+	return val
+}
+
+func (vm *VM) peek(distance int) runtime.RuntimeValue {
+	return vm.stack[len(vm.stack)-1-distance]
+}
+
+// Run runs a program compiled by `compiler.Compile` and returns the value it returned
+func (vm *VM) Run(function *compiler.Function) (*runtime.RuntimeValue, *runtime.RuntimeError) {
+	closure := &Closure{Function: function}
+
+	vm.stack = vm.stack[:0]
+	vm.frames = vm.frames[:0]
+	vm.openUpvalues = nil
+
+	vm.push(*runtime.NewRuntimeValue(closure))
+	vm.frames = append(vm.frames, frame{closure: closure})
+
+	return vm.run()
+}
+
+func (vm *VM) run() (*runtime.RuntimeValue, *runtime.RuntimeError) {
+	f := &vm.frames[len(vm.frames)-1]
+	chunk := f.closure.Function.Chunk
+	globals := vm.Runtime.GlobalEnv()
+
+	readU8 := func() int {
+		f.ip++
+		return int(chunk.Code[f.ip-1])
+	}
+
+	readU16 := func() int {
+		f.ip += 2
+		return chunk.ReadU16(f.ip - 2)
+	}
+
+	newError := func(msg diagnostics.Message, at string) *runtime.RuntimeError {
+		return runtime.NewRuntimeError(msg, at, chunk.Spans[f.ip-1])
+	}
+
+	for {
+		op := compiler.OpCode(chunk.Code[f.ip])
+		f.ip++
+
+		switch op {
+		case compiler.OP_CONSTANT:
+			vm.push(chunk.Constants[readU16()])
+		case compiler.OP_NIL:
+			vm.push(runtime.RuntimeValue{Value: nil})
+		case compiler.OP_TRUE:
+			vm.push(runtime.RuntimeValue{Value: true})
+		case compiler.OP_FALSE:
+			vm.push(runtime.RuntimeValue{Value: false})
+		case compiler.OP_POP:
+			vm.pop()
+		case compiler.OP_GET_LOCAL:
+			vm.push(vm.stack[f.base+readU8()])
+		case compiler.OP_SET_LOCAL:
+			vm.stack[f.base+readU8()] = vm.peek(0)
+		case compiler.OP_GET_UPVALUE:
+			val := f.closure.Upvalues[readU8()].get(vm.stack)
+			if undeclared, ok := val.Value.(*compiler.Undeclared); ok {
+				return nil, newError(runtime.UNDEFINED_IDENTIFIER, undeclared.Name)
+			}
+
+			vm.push(val)
+		case compiler.OP_SET_UPVALUE:
+			upvalue := f.closure.Upvalues[readU8()]
+			if undeclared, ok := upvalue.get(vm.stack).Value.(*compiler.Undeclared); ok {
+				return nil, newError(runtime.UNDEFINED_IDENTIFIER, undeclared.Name)
+			}
+
+			if upvalue.isOpen {
+				vm.stack[upvalue.slot] = vm.peek(0)
+			} else {
+				upvalue.closed = vm.peek(0)
+			}
+		case compiler.OP_GET_GLOBAL:
+			name := chunk.Constants[readU16()].Value.(string)
+
+			if val, ok := globals.Vars[name]; ok {
+				vm.push(val)
+			} else if nativeFn, ok := vm.Runtime.GetNativeFn(name); ok {
+				vm.push(*runtime.NewRuntimeValue(nativeFn))
+			} else {
+				return nil, newError(runtime.UNDEFINED_IDENTIFIER, name)
+			}
+		case compiler.OP_DEFINE_GLOBAL:
+			name := chunk.Constants[readU16()].Value.(string)
+
+			if _, ok := globals.Vars[name]; ok {
+				return nil, newError(runtime.IDENTIFIER_ALREADY_EXISTS, name)
+			}
+
+			globals.SetVar(name, vm.pop())
+		case compiler.OP_SET_GLOBAL:
+			name := chunk.Constants[readU16()].Value.(string)
+
+			if _, ok := globals.Vars[name]; !ok {
+				return nil, newError(runtime.UNDEFINED_IDENTIFIER, name)
+			}
+
+			globals.SetVar(name, vm.peek(0))
+		case compiler.OP_ADD, compiler.OP_SUBTRACT, compiler.OP_MULTIPLY, compiler.OP_LESS, compiler.OP_LESS_EQUAL, compiler.OP_GREATER, compiler.OP_GREATER_EQUAL:
+			var result interface{}
+
+			switch left := vm.peek(1).Value.(type) {
+			case int64:
+				if right, ok := vm.peek(0).Value.(int64); ok {
+					result = numOp(op, left, right)
+				}
+			case float64:
+				if right, ok := vm.peek(0).Value.(float64); ok {
+					result = numOp(op, left, right)
+				}
+			}
+
+			// mixed numbers and other operands are left to the evaluator
+			if result == nil {
+				if err := vm.binaryOp(op, chunk.Spans[f.ip-1]); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			vm.stack = vm.stack[:len(vm.stack)-1]
+			vm.stack[len(vm.stack)-1] = runtime.RuntimeValue{Value: result}
+		case compiler.OP_DIVIDE, compiler.OP_MODULO, compiler.OP_EQUAL, compiler.OP_NOT_EQUAL, compiler.OP_POWER,
+			compiler.OP_BIT_AND, compiler.OP_BIT_OR, compiler.OP_BIT_XOR, compiler.OP_SHIFT_LEFT, compiler.OP_SHIFT_RIGHT:
+			if err := vm.binaryOp(op, chunk.Spans[f.ip-1]); err != nil {
+				return nil, err
+			}
+		case compiler.OP_NEGATE, compiler.OP_NOT, compiler.OP_BIT_NOT:
+			operator := tokens.MINUS
+			switch op {
+			case compiler.OP_NOT:
+				operator = tokens.BANG
+			case compiler.OP_BIT_NOT:
+				operator = tokens.BIT_NOT
+			}
+
+			val, err := evaluator.UnaryOp(operator, vm.pop(), vm.Runtime.TruthyLogic, chunk.Spans[f.ip-1])
+			if err != nil {
+				return nil, err
+			}
+
+			vm.push(*val)
+		case compiler.OP_AND, compiler.OP_OR:
+			offset := readU16()
+			left := vm.peek(0)
+
+			var leftBool bool
+
+			if vm.Runtime.TruthyLogic {
+				leftBool = runtime.IsTruthy(left)
+			} else {
+				val, isBool := left.Value.(bool)
+				if !isBool {
+					return nil, newError(runtime.OperandsMustBeOfErrBuilder("bool"), logicalOperator(op).Literal())
+				}
+				leftBool = val
+			}
+
+			// `false && ...` and `true || ...` are decided by the left operand alone
+			if (op == compiler.OP_AND && !leftBool) || (op == compiler.OP_OR && leftBool) {
+				f.ip += offset
+			} else {
+				vm.pop()
+			}
+		case compiler.OP_ASSERT_BOOL:
+			operator := tokens.TokenType(readU8())
+
+			if _, isBool := vm.peek(0).Value.(bool); !isBool && !vm.Runtime.TruthyLogic {
+				return nil, newError(runtime.OperandsMustBeOfErrBuilder("bool"), operator.Literal())
+			}
+		case compiler.OP_INCREMENT, compiler.OP_DECREMENT:
+			name := chunk.Constants[readU16()].Value.(string)
+
+			delta := int64(1)
+			if op == compiler.OP_DECREMENT {
+				delta = -1
+			}
+
+			val, err := evaluator.IncrementOp(vm.peek(0), delta, name, chunk.Spans[f.ip-1])
+			if err != nil {
+				return nil, err
+			}
+
+			vm.stack[len(vm.stack)-1] = *val
+		case compiler.OP_PRINT:
+			fmt.Fprintln(vm.Runtime.Stdout, vm.pop())
+		case compiler.OP_JUMP:
+			offset := readU16()
+			f.ip += offset
+		case compiler.OP_JUMP_IF_FALSE:
+			offset := readU16()
+			expectedIdx := readU16()
+			condition := vm.pop()
+
+			conditionVal, isBool := condition.Value.(bool)
+			if !isBool {
+				return nil, newError(runtime.ExpectedExprErrBuilder(chunk.Constants[expectedIdx].Value.(string)), condition.String())
+			}
+
+			if !conditionVal {
+				f.ip += offset
+			}
+		case compiler.OP_LOOP:
+			offset := readU16()
+			f.ip -= offset
+		case compiler.OP_CALL:
+			argsCount := readU8()
+
+			if err := vm.call(argsCount, chunk.Spans[f.ip-1]); err != nil {
+				return nil, err
+			}
+
+			f = &vm.frames[len(vm.frames)-1]
+			chunk = f.closure.Function.Chunk
+		case compiler.OP_CLOSURE:
+			function := chunk.Constants[readU16()].Value.(*compiler.Function)
+			closure := &Closure{
+				Function: function,
+				Upvalues: make([]*Upvalue, function.UpvalueCount),
+			}
+
+			for i := range closure.Upvalues {
+				isLocal := readU8()
+				index := readU8()
+
+				if isLocal == 1 {
+					closure.Upvalues[i] = vm.captureUpvalue(f.base + index)
+				} else {
+					closure.Upvalues[i] = f.closure.Upvalues[index]
+				}
+			}
+
+			vm.push(*runtime.NewRuntimeValue(closure))
+		case compiler.OP_CLOSE_UPVALUE:
+			vm.closeUpvalues(len(vm.stack) - 1)
+			vm.pop()
+		case compiler.OP_RETURN:
+			result := vm.pop()
+			vm.closeUpvalues(f.base)
+
+			vm.frames = vm.frames[:len(vm.frames)-1]
+			if len(vm.frames) == 0 {
+				vm.stack = vm.stack[:0]
+				return &result, nil
+			}
+
+			vm.stack = vm.stack[:f.base]
+			vm.push(result)
+
+			f = &vm.frames[len(vm.frames)-1]
+			chunk = f.closure.Function.Chunk
+		case compiler.OP_LIST:
+			count := readU16()
+
+			elements := make([]runtime.RuntimeValue, count)
+			copy(elements, vm.stack[len(vm.stack)-count:])
+			vm.stack = vm.stack[:len(vm.stack)-count]
+
+			vm.push(*runtime.NewRuntimeValue(runtime.NewList(elements)))
+		case compiler.OP_BUILD_STRING:
+			count := readU16()
+			start := len(vm.stack) - count
+
+			var b strings.Builder
+			for _, part := range vm.stack[start:] {
+				b.WriteString(part.String())
+			}
+			vm.stack = vm.stack[:start]
+
+			vm.push(*runtime.NewRuntimeValue(b.String()))
+		case compiler.OP_MAP:
+			count := readU16()
+			start := len(vm.stack) - 2*count
+
+			m := runtime.NewMap()
+			for i := start; i < len(vm.stack); i += 2 {
+				key := vm.stack[i]
+				if keyErr := runtime.ValidateMapKey(key); keyErr != nil {
+					return nil, newError(runtime.MessageOf(keyErr), key.String())
+				}
+
+				m.Set(key, vm.stack[i+1])
+			}
+			vm.stack = vm.stack[:start]
+
+			vm.push(*runtime.NewRuntimeValue(m))
+		case compiler.OP_INDEX:
+			index := vm.pop()
+			target := vm.pop()
+
+			val, err := evaluator.IndexOp(target, index, chunk.Spans[f.ip-1])
+			if err != nil {
+				return nil, err
+			}
+
+			vm.push(*val)
+		case compiler.OP_SET_INDEX:
+			val := vm.pop()
+			index := vm.pop()
+			target := vm.pop()
+
+			if err := evaluator.SetIndexOp(target, index, val, chunk.Spans[f.ip-1]); err != nil {
+				return nil, err
+			}
+
+			vm.push(val)
+		default:
+			return nil, newError(runtime.INVALID_OPERATOR, fmt.Sprintf("%d", op))
+		}
+	}
+}
+
+func logicalOperator(op compiler.OpCode) tokens.TokenType {
+	if op == compiler.OP_OR {
+		return tokens.OR
+	}
+
+	return tokens.AND
+}
+
+// applies the operators of the fast path to two ints or two floats
+func numOp[T int64 | float64](op compiler.OpCode, left T, right T) interface{} {
+	switch op {
+	case compiler.OP_ADD:
+		return left + right
+	case compiler.OP_SUBTRACT:
+		return left - right
+	case compiler.OP_MULTIPLY:
+		return left * right
+	case compiler.OP_LESS:
+		return left < right
+	case compiler.OP_LESS_EQUAL:
+		return left <= right
+	case compiler.OP_GREATER:
+		return left > right
+	default:
+		return left >= right
+	}
+}
+
+// applies a binary operator through the evaluator, so errors and edge cases match the tree walker
+func (vm *VM) binaryOp(op compiler.OpCode, span tokens.Span) *runtime.RuntimeError {
+	right := vm.pop()
+	left := vm.pop()
+
+	val, err := evaluator.BinaryOp(compiler.BinaryOpOperators[op], left, right, span)
+	if err != nil {
+		return err
+	}
+
+	vm.push(*val)
+	return nil
+}
+
+// calls the value below the `argsCount` arguments on top of the stack. compiled functions get a new
+// frame, native functions are run right away and their result replaces the callee and arguments
+func (vm *VM) call(argsCount int, span tokens.Span) *runtime.RuntimeError {
+	base := len(vm.stack) - argsCount - 1
+	callee := vm.stack[base]
+
+	switch fn := callee.Value.(type) {
+	case *Closure:
+		if argsCount != fn.Function.Arity {
+			return runtime.NewRuntimeError(runtime.ArgumentsCountErrBuilder(fn.Function.Arity, argsCount), fn.String(), span)
+		}
+
+		if len(vm.frames) >= FRAMES_MAX {
+			return runtime.NewRuntimeError(runtime.STACK_OVERFLOW, fn.String(), span)
+		}
+
+		vm.frames = append(vm.frames, frame{closure: fn, base: base})
+	case *runtime.NativeFnMapping:
+		if fn.Arity >= 0 && argsCount != fn.Arity {
+			return runtime.NewRuntimeError(runtime.ArgumentsCountErrBuilder(fn.Arity, argsCount), fn.Name, span)
+		}
+
+		// natives may hold on to their arguments, so they get a copy rather than a view into the stack
+		args := make([]runtime.RuntimeValue, argsCount)
+		copy(args, vm.stack[base+1:])
+
+		val, err := fn.Fn(args)
+		if err != nil {
+			return runtime.NewRuntimeError(runtime.MessageOf(err), fn.Name, span)
+		}
+
+		vm.stack = vm.stack[:base]
+		vm.push(runtime.FromGo(val.Value))
+	default:
+		if callee.Value == nil {
+			return runtime.NewRuntimeError(runtime.NOT_CALLABLE, "nada", span)
+		}
+
+		return runtime.NewRuntimeError(runtime.NOT_CALLABLE, callee.String(), span)
+	}
+
+	return nil
+}
+
+// returns the upvalue for the stack slot, reusing the existing one if another closure already
+// captured the slot
+func (vm *VM) captureUpvalue(slot int) *Upvalue {
+	var prev *Upvalue
+	upvalue := vm.openUpvalues
+
+	for upvalue != nil && upvalue.slot > slot {
+		prev = upvalue
+		upvalue = upvalue.next
+	}
+
+	if upvalue != nil && upvalue.slot == slot {
+		return upvalue
+	}
+
+	created := &Upvalue{slot: slot, isOpen: true, next: upvalue}
+	if prev == nil {
+		vm.openUpvalues = created
+	} else {
+		prev.next = created
+	}
+
+	return created
+}
+
+// moves every captured variable at or above `last` off the stack
+func (vm *VM) closeUpvalues(last int) {
+	for vm.openUpvalues != nil && vm.openUpvalues.slot >= last {
+		upvalue := vm.openUpvalues
+		upvalue.closed = vm.stack[upvalue.slot]
+		upvalue.isOpen = false
+		vm.openUpvalues = upvalue.next
+	}
+}
@@ -0,0 +1,392 @@
+package vm_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/0xmukesh/interpreter/brtlang"
+)
+
+// vibeCheck returns the current time, so it is swapped out for a constant to keep the output of
+// both backends comparable
+func stubClock(args []brtlang.Value) (brtlang.Value, error) {
+	return brtlang.Value{Value: int64(0)}, nil
+}
+
+func readExamples(tb testing.TB) map[string][]byte {
+	tb.Helper()
+
+	paths, err := filepath.Glob(filepath.Join("..", "..", "examples", "*.brt"))
+	if err != nil || len(paths) == 0 {
+		tb.Fatalf("no examples found: %v", err)
+	}
+
+	examples := make(map[string][]byte, len(paths))
+	for _, path := range paths {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			tb.Fatal(err)
+		}
+
+		examples[strings.TrimSuffix(filepath.Base(path), ".brt")] = src
+	}
+
+	return examples
+}
+
+func run(src []byte, out io.Writer, opts ...brtlang.Option) error {
+	opts = append(opts, brtlang.WithStdout(out), brtlang.WithStderr(out), brtlang.WithNativeFn("vibeCheck", 0, stubClock))
+	return brtlang.New(opts...).Run(src)
+}
+
+func TestExamplesMatchTreeWalker(t *testing.T) {
+	for name, src := range readExamples(t) {
+		t.Run(name, func(t *testing.T) {
+			var treeOut, vmOut bytes.Buffer
+
+			treeErr := run(src, &treeOut)
+			vmErr := run(src, &vmOut, brtlang.WithVM())
+
+			if (treeErr == nil) != (vmErr == nil) {
+				t.Fatalf("tree walker error: %v, vm error: %v", treeErr, vmErr)
+			}
+
+			if treeOut.String() != vmOut.String() {
+				t.Fatalf("output differs\ntree walker:\n%s\nvm:\n%s", treeOut.String(), vmOut.String())
+			}
+		})
+	}
+}
+
+func TestProgramsMatchTreeWalker(t *testing.T) {
+	programs := map[string]string{
+		"closures": `
+			skibidi makeCounter() {
+			  rizz count = 0;
+			  bussin skibidi() {
+			    count++;
+			    bussin count;
+			  };
+			}
+			rizz c = makeCounter();
+			c();
+			yap(c());
+			yap(makeCounter()());`,
+		"closures per iteration": `
+			rizz fns = [];
+			chillin (rizz i = 0; i < 3; i++) {
+			  rizz j = i;
+			  push(fns, skibidi() { bussin j; });
+			}
+			yap(fns[0]());
+			yap(fns[2]());`,
+		"recursion": `
+			skibidi fact(n) {
+			  edging (n <= 1) {
+			    bussin 1;
+			  }
+			  bussin n * fact(n - 1);
+			}
+			yap(fact(10));`,
+		"mutual recursion": `
+			skibidi isEven(n) {
+			  edging (n == 0) { bussin bet; }
+			  bussin isOdd(n - 1);
+			}
+			skibidi isOdd(n) {
+			  edging (n == 0) { bussin cap; }
+			  bussin isEven(n - 1);
+			}
+			yap(isEven(10));
+			skibidi outer() {
+			  skibidi ping(n) {
+			    edging (n == 0) { bussin "done"; }
+			    bussin pong(n - 1);
+			  }
+			  skibidi pong(n) { bussin ping(n); }
+			  bussin ping(3);
+			}
+			yap(outer());`,
+		"scoped functions": `
+			skibidi greet() { bussin "global"; }
+			{
+			  yap(greet());
+			  skibidi greet() { bussin "block"; }
+			  yap(greet());
+			}
+			yap(greet());
+			chillin (rizz i = 0; i < 2; i++) {
+			  skibidi sq() { bussin i * i; }
+			  yap(sq());
+			}`,
+		"call before declaration": `
+			yap(later());
+			skibidi later() { bussin 1; }`,
+		"call before sibling declaration": `
+			{ skibidi a() { bussin b(); } yap(a()); skibidi b() { bussin 2; } }`,
+		"recursive function expression": `
+			{ rizz f = skibidi(n) { edging (n == 0) { bussin 0; } bussin f(n - 1); }; yap(f(3)); }`,
+		"assignment expressions": `
+			rizz x = 1; rizz y = 2;
+			yap(x = 5); yap(x);
+			x = y = 3; yap(x); yap(y);
+			rizz xs = [1, 2]; yap(xs[0] = 7); yap(xs);
+			skibidi f(a) { bussin a; }
+			yap(f(x = 10)); yap(x);`,
+		"self referencing list": `
+			rizz xs = [1]; push(xs, xs);
+			yap(xs); yap(xs == xs);`,
+		"self referencing map": `
+			rizz m = {}; m["self"] = m;
+			yap("${m}"); yap(m == m);`,
+		"early return": `
+			skibidi find(xs, target) {
+			  chillin (rizz i = 0; i < len(xs); i++) {
+			    edging (xs[i] == target) {
+			      bussin i;
+			    }
+			  }
+			  bussin -1;
+			}
+			skibidi nothing() { rizz a = 1; }
+			yap(find([4, 5, 6], 5) + 1);
+			yap(find([4, 5, 6], 7));
+			yap(nothing());`,
+		"top-level return": `
+			yap("before");
+			bussin nada;
+			yap("after");`,
+		"collections": `
+			rizz xs = [1, "two", [3]];
+			xs[0] = xs[0] + 10;
+			push(xs, {"k": nada});
+			yap(xs);
+			yap(xs[3]["k"]);
+			yap(keys({2: 1, "a": 2}));`,
+		"calls as operands": `
+			skibidi f(x) { yap("f"); bussin x * 2; }
+			skibidi g(x) { yap("g"); bussin x + 1; }
+			rizz x = f(1) + g(2);
+			yap(x);
+			edging (f(2) > 3) { yap("big"); }
+			yap([f(1), g(1)]);
+			skibidi h(a, b) { bussin a - b; }
+			yap(h(f(1), g(1)));
+			yap(!(f(1) == 2));`,
+		"short circuit": `
+			skibidi boom() { yap("boom"); bussin bet; }
+			yap(cap && boom());
+			yap(bet || boom());
+			yap(bet && boom());`,
+		"scopes": `
+			rizz a = "global";
+			{
+			  rizz a = "outer";
+			  {
+			    rizz a = "inner";
+			    yap(a);
+			  }
+			  yap(a);
+			}
+			yap(a);`,
+		"break and continue": `
+			chillin (rizz i = 0; i < 10; i++) {
+			  edging (i % 2 == 0) { skrrt; }
+			  edging (i > 7) { yeet; }
+			  rizz sq = i * i;
+			  yap(sq);
+			}
+			rizz n = 0;
+			vibin (bet) {
+			  n++;
+			  {
+			    rizz inner = n;
+			    edging (inner == 3) { skrrt; }
+			    edging (inner > 5) { yeet; }
+			    yap(inner);
+			  }
+			}
+			yap(n);`,
+		"break in nested loops": `
+			chillin (rizz a = 0; a < 3; a++) {
+			  chillin (rizz b = 0; b < 3; b++) {
+			    edging (b == 1) { skrrt; }
+			    edging (a == 1) { yeet; }
+			    yap(a * 10 + b);
+			  }
+			}
+			rizz fns = [];
+			chillin (rizz k = 0; k < 5; k++) {
+			  rizz j = k;
+			  push(fns, skibidi() { bussin j; });
+			  edging (k == 2) { yeet; }
+			}
+			yap(len(fns));
+			yap(fns[2]());`,
+		"numbers": `
+			yap(7 / 2);
+			yap(-7 / 2);
+			yap(7 / 2.0);
+			yap(7 % 4);
+			yap(7.5 % 2);
+			yap(0.1 + 0.2);
+			yap(2.5 * 2);
+			yap(1 == 1.0);
+			yap(3 < 3.5);
+			yap([1, 2.0] == [1.0, 2]);
+			rizz m = {1: "int"};
+			m[1.0] = "float";
+			yap(m);
+			rizz f = 1.5;
+			f++;
+			yap(f);
+			yap(["a", "b", "c"][len("abcd") / 2]);
+			yap(9223372036854775807 + 1);
+			yap(-(4 - 0.5));
+			yap(0xff + 0b1010 + 0o17 + 1_000);
+			yap(6.02e23 / 1e-7);`,
+		"float division by zero": `yap(1 / 0.0);`,
+		"big numbers": `
+			rizz price = 1.10d;
+			yap(price * 3d);
+			yap(0.1d + 0.2d);
+			yap(1d / 3d);
+			yap(-7.5d % 2d);
+			yap(1.10d == 1.1d);
+			rizz big = 9223372036854775807n;
+			yap(big * big);
+			yap(-big / 2n > -big);
+			rizz n = 1n;
+			n++;
+			yap(n);
+			yap(bigint(2.9d) + bigint("40"));
+			yap(decimal(0.1) + decimal("0.2"));
+			yap({1.5d: "a", 1.50d: "b"});
+			yap("${big + 1n} ${[1n, 2.50d]}");`,
+		"mixed numbers": `yap(1n + 1);`,
+		"bitwise": `
+			yap([6 & 3, 6 | 3, 6 ^ 3, ~5]);
+			yap([1 << 4 + 1, -16 >> 2, 1 << 64]);
+			yap([2 ** 10, -2 ** 2, 2 ** 3 ** 2, 3 ** 41]);
+			yap([bigint(2) ** 100n, ~0n & 7n, 1n << 70n]);
+			yap(1 | 2 == 3);`,
+		"negative exponent": `yap(2 ** -1);`,
+		"assignment exprs": `
+			rizz i = 5;
+			yap([i++, i, ++i, i--, --i]);
+			rizz total = 1;
+			yap(total += 2 * i);
+			rizz s = "a";
+			s += "b" + "c";
+			yap(s);
+			rizz x = 2;
+			rizz y = 3;
+			x *= y += 1;
+			x /= 3;
+			yap([x, y, x %= 2]);
+			chillin (rizz j = 0; j < 10; j += 4) { yap(j); }
+			chillin (rizz k = 2; k > 0; --k) { yap(k); }
+			skibidi counter() {
+				rizz n = 0;
+				bussin skibidi() { bussin n++; };
+			}
+			rizz c = counter();
+			c();
+			yap(c());
+			rizz big = 1n;
+			big -= 3n;
+			yap(big++ + big);
+			yap(2 ** ++i);`,
+		"compound on string":  `rizz s = "a"; s -= 1;`,
+		"increment on string": `rizz s = "a"; yap(s++);`,
+		"bitwise on floats":   `yap(1.5 & 1);`,
+		"negative shift":      `yap(1n << -1n);`,
+		"big shifts": `
+			yap([8n >> 18446744073709551619n, -8n >> 18446744073709551619n, 0n << 18446744073709551616n]);
+			yap([1n ** 18446744073709551616n, bigint(-1) ** 3n, 10n ** 20n]);`,
+		"big int too large": `yap(1n << 18446744073709551616n);`,
+		"interpolation": `
+			rizz score = 21;
+			rizz name = "bestie";
+			yap("score: ${score * 2}, ${name}!");
+			yap("nested ${"inner ${score + 1}"} and {braces} \${escaped}");
+			yap("${[1, "two", nada]} ${{"k": cap}["k"]} ${len(name)}"[0]);
+			skibidi greet(who) { bussin "sup ${who}"; }
+			yap(greet("fam"));`,
+		"runtime error": `
+			yap("before");
+			yap(1 + "a");
+			yap("after");`,
+		"not callable": `rizz x = 1; x();`,
+		"deep recursion": `
+			skibidi depth(n) { edging (n == 0) { bussin 0; } bussin depth(n - 1) + 1; }
+			yap(depth(9000));`,
+		"stack overflow": `skibidi f() { bussin f(); } f();`,
+		"error in condition": `
+			skibidi f() { bussin 1 / 0; }
+			edging (f() == 1) { yap(1); } amogus { yap(2); }`,
+		"arguments count": `skibidi f(a) { bussin a; } f(1, 2);`,
+	}
+
+	// programs which end with an error on purpose, every other one has to run to the end on both backends
+	failing := map[string]bool{
+		"call before declaration":         true,
+		"call before sibling declaration": true,
+		"float division by zero":          true,
+		"mixed numbers":                   true,
+		"negative exponent":               true,
+		"bitwise on floats":               true,
+		"negative shift":                  true,
+		"big int too large":               true,
+		"compound on string":              true,
+		"increment on string":             true,
+		"runtime error":                   true,
+		"not callable":                    true,
+		"error in condition":              true,
+		"stack overflow":                  true,
+		"arguments count":                 true,
+	}
+
+	for name, src := range programs {
+		t.Run(name, func(t *testing.T) {
+			var treeOut, vmOut bytes.Buffer
+
+			treeErr := run([]byte(src), &treeOut)
+			vmErr := run([]byte(src), &vmOut, brtlang.WithVM())
+
+			if (treeErr != nil) != failing[name] || (vmErr != nil) != failing[name] {
+				t.Fatalf("tree walker error: %v, vm error: %v, expected an error: %t", treeErr, vmErr, failing[name])
+			}
+
+			if treeOut.String() != vmOut.String() {
+				t.Fatalf("output differs\ntree walker:\n%s\nvm:\n%s", treeOut.String(), vmOut.String())
+			}
+		})
+	}
+}
+
+func BenchmarkExamples(b *testing.B) {
+	backends := []struct {
+		name string
+		opts []brtlang.Option
+	}{
+		{"tree", nil},
+		{"vm", []brtlang.Option{brtlang.WithVM()}},
+	}
+
+	for name, src := range readExamples(b) {
+		for _, backend := range backends {
+			b.Run(name+"/"+backend.name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if err := run(src, io.Discard, backend.opts...); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}